@@ -3,24 +3,168 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/example/draftpractice/internal/analytics"
 	"github.com/example/draftpractice/internal/draft"
 	"github.com/example/draftpractice/internal/heroes"
+	"github.com/example/draftpractice/internal/history"
 	"github.com/example/draftpractice/internal/server"
 )
 
+// counterBotTemperature — насколько часто CounterBot отходит от топ-1 варианта.
+const counterBotTemperature = 0.02
+
 func main() {
-	if err := heroes.Init(); err != nil {
+	// Без сети каталог поднимается из кэша или встроенного снимка,
+	// а OpenDota опрашивается в фоне, пока не ответит.
+	catalog := heroes.NewCatalog(heroProvider()).WithCache(heroCachePath())
+	if err := catalog.LoadOrFallback(); err != nil {
 		log.Fatalf("failed to load heroes: %v", err)
 	}
+	catalog.StartRefresher(0)
+
+	matchups, err := loadMatchups(catalog)
+	if err != nil {
+		log.Fatalf("failed to load matchups: %v", err)
+	}
+
+	winModel, err := loadWinModel(matchups)
+	if err != nil {
+		log.Fatalf("failed to load win probability model: %v", err)
+	}
+
+	repo, closeRepo, err := openRepository()
+	if err != nil {
+		log.Fatalf("failed to open session repository: %v", err)
+	}
+	defer closeRepo()
+
+	draftStore := draft.NewStoreWithConfig(draft.StoreConfig{Repository: repo, Catalog: catalog})
+	// Дописываем снимки сессий до закрытия репозитория
+	defer draftStore.Flush()
+	draftStore.RegisterBot("counter", func() draft.Bot {
+		return draft.NewCounterBot(matchups, counterBotTemperature)
+	})
+	draftStore.RegisterBot("mcts", func() draft.Bot {
+		// Без обученных весов эвристика ролей оценивает драфт лучше пустой модели.
+		if os.Getenv("WINPROB_MODEL") == "" {
+			return draft.NewMCTSBot(draft.DefaultEvaluator(catalog, matchups))
+		}
+		return draft.NewMCTSBot(winModel.Predict)
+	})
+
+	archive, closeArchive, err := openArchive()
+	if err != nil {
+		log.Fatalf("failed to open draft history: %v", err)
+	}
+	defer closeArchive()
+	draftStore.OnCompleted(history.Recorder(archive, winModel))
+	draftStore.OnResult(history.ResultRecorder(archive))
 
-	draftStore := draft.NewStore()
+	// Боты уже зарегистрированы — можно поднимать незавершённые драфты.
+	restored, err := draftStore.Restore()
+	if err != nil {
+		log.Fatalf("failed to restore sessions: %v", err)
+	}
+	if restored > 0 {
+		log.Printf("restored %d in-progress sessions", restored)
+	}
 
 	handler := server.NewHandler(server.RouterConfig{
 		DraftStore: draftStore,
+		Heroes:     catalog,
+		Matchups:   matchups,
+		WinModel:   winModel,
+		History:    archive,
 	})
 
 	if err := http.ListenAndServe(":8080", handler); err != nil {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
+
+// openRepository открывает файл bbolt из DRAFT_DB, если он задан, иначе
+// сессии живут только в памяти процесса.
+func openRepository() (draft.SessionRepository, func(), error) {
+	path := os.Getenv("DRAFT_DB")
+	if path == "" {
+		return draft.NewMemoryRepository(), func() {}, nil
+	}
+	repo, err := draft.OpenBoltRepository(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, func() { repo.Close() }, nil
+}
+
+// openArchive открывает архив истории драфтов из DRAFT_HISTORY_DB, если он
+// задан, иначе история хранится в памяти процесса.
+func openArchive() (history.Archive, func(), error) {
+	path := os.Getenv("DRAFT_HISTORY_DB")
+	if path == "" {
+		return history.NewMemoryArchive(), func() {}, nil
+	}
+	archive, err := history.OpenBoltArchive(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return archive, func() { archive.Close() }, nil
+}
+
+// heroProvider выбирает источник каталога героев по HERO_CATALOG:
+// "snapshot" — встроенный в бинарник снимок, путь — JSON-файл в формате
+// heroStats, пусто — OpenDota (OPENDOTA_API_URL).
+func heroProvider() heroes.Provider {
+	switch source := os.Getenv("HERO_CATALOG"); source {
+	case "":
+		return heroes.NewOpenDotaProvider(os.Getenv("OPENDOTA_API_URL"))
+	case "snapshot":
+		return heroes.NewSnapshotProvider()
+	default:
+		return heroes.NewFileProvider(source)
+	}
+}
+
+// heroCachePath — файл, куда сохраняется каждая успешная загрузка каталога:
+// HERO_CACHE_FILE или heroes.json в пользовательском каталоге кэша.
+func heroCachePath() string {
+	if path := os.Getenv("HERO_CACHE_FILE"); path != "" {
+		return path
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "draftpractice", "heroes.json")
+}
+
+// loadMatchups читает матрицу из HERO_MATCHUPS_FILE, если он задан
+// (офлайн-режим), иначе подтягивает её из OpenDota в фоне.
+func loadMatchups(catalog *heroes.Catalog) (*heroes.Matrix, error) {
+	if path := os.Getenv("HERO_MATCHUPS_FILE"); path != "" {
+		return heroes.LoadMatchupFile(path)
+	}
+	return heroes.StartMatchups(heroes.MatchupConfig{
+		Catalog:         catalog,
+		BaseURL:         os.Getenv("OPENDOTA_API_URL"),
+		SynergyURL:      os.Getenv("HERO_SYNERGY_URL"),
+		RequestInterval: time.Second,
+	}), nil
+}
+
+// loadWinModel читает веса из WINPROB_MODEL (JSON или gob). Без файла
+// используется модель только на матрице матчапов.
+func loadWinModel(matchups *heroes.Matrix) (*analytics.Model, error) {
+	model := analytics.NewModel()
+	if path := os.Getenv("WINPROB_MODEL"); path != "" {
+		loaded, err := analytics.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		model = loaded
+	}
+	return model.WithMatchups(matchups), nil
+}
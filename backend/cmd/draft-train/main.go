@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/draftpractice/internal/analytics"
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+// draft-train обучает модель вероятности победы на локальной выгрузке матчей
+// и пишет файл весов для draft-api (переменная WINPROB_MODEL). Сеть не нужна.
+func main() {
+	input := flag.String("input", "", "matches dump: JSONL (OpenDota proMatches + match details) or CSV")
+	format := flag.String("format", "", "input format: jsonl or csv (by extension if empty)")
+	output := flag.String("output", "winprob.json", "weights file to write (.json or .gob)")
+	matchupsFile := flag.String("matchups", "", "optional matchup matrix file for synergy/counter features")
+	holdout := flag.Float64("holdout", 0.2, "share of matches kept for evaluation, in [0, 1)")
+	epochs := flag.Int("epochs", 30, "training epochs")
+	lr := flag.Float64("lr", 0.05, "initial learning rate")
+	l2 := flag.Float64("l2", 1e-4, "L2 regularization of hero weights")
+	seed := flag.Int64("seed", 1, "random seed for shuffling and the holdout split")
+	flag.Parse()
+
+	if *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if !(*holdout >= 0 && *holdout < 1) {
+		fmt.Fprintf(os.Stderr, "-holdout must be in [0, 1), got %v\n", *holdout)
+		os.Exit(2)
+	}
+
+	matches, skipped, err := readMatches(*input, *format)
+	if err != nil {
+		log.Fatalf("failed to read matches: %v", err)
+	}
+	fmt.Printf("[TRAIN] %d matches loaded, %d skipped\n", len(matches), skipped)
+
+	cfg := analytics.TrainConfig{
+		Epochs:       *epochs,
+		LearningRate: *lr,
+		L2:           *l2,
+		Holdout:      *holdout,
+		Seed:         *seed,
+	}
+	if *matchupsFile != "" {
+		matrix, err := heroes.LoadMatchupFile(*matchupsFile)
+		if err != nil {
+			log.Fatalf("failed to load matchups: %v", err)
+		}
+		cfg.Matchups = matrix
+	}
+
+	model, train, test, err := analytics.Train(matches, cfg)
+	if err != nil {
+		log.Fatalf("training failed: %v", err)
+	}
+
+	fmt.Printf("[TRAIN] train:   %d matches, log-loss %.4f, accuracy %.2f%%\n",
+		train.Samples, train.LogLoss, train.Accuracy*100)
+	if test.Samples > 0 {
+		fmt.Printf("[TRAIN] holdout: %d matches, log-loss %.4f, accuracy %.2f%%\n",
+			test.Samples, test.LogLoss, test.Accuracy*100)
+	}
+
+	if err := model.Save(*output); err != nil {
+		log.Fatalf("failed to write weights: %v", err)
+	}
+	fmt.Printf("[TRAIN] weights written to %s\n", *output)
+}
+
+func readMatches(path, format string) ([]analytics.Match, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	switch format {
+	case "csv":
+		return analytics.ReadCSV(f)
+	case "jsonl", "json", "ndjson":
+		return analytics.ReadJSONL(f)
+	default:
+		return nil, 0, fmt.Errorf("unknown format %q", format)
+	}
+}
@@ -2,4 +2,9 @@ module github.com/example/draftpractice
 
 go 1.21
 
-require github.com/gorilla/websocket v1.5.3
+require (
+	github.com/gorilla/websocket v1.5.3
+	go.etcd.io/bbolt v1.3.10
+)
+
+require golang.org/x/sys v0.21.0 // indirect
@@ -0,0 +1,200 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Match — один матч выгрузки: герои сторон и победитель.
+type Match struct {
+	MatchID    int64
+	Radiant    []int
+	Dire       []int
+	RadiantWin bool
+}
+
+// openDotaMatch — строка /api/proMatches, дополненная деталями матча
+// (/api/matches/{id}). Герои берутся из picks_bans, затем из players,
+// затем из radiant_team/dire_team (формат /api/publicMatches).
+type openDotaMatch struct {
+	MatchID    int64 `json:"match_id"`
+	RadiantWin *bool `json:"radiant_win"`
+	PicksBans  []struct {
+		IsPick bool `json:"is_pick"`
+		HeroID int  `json:"hero_id"`
+		Team   int  `json:"team"`
+	} `json:"picks_bans"`
+	Players []struct {
+		HeroID     int   `json:"hero_id"`
+		PlayerSlot int   `json:"player_slot"`
+		IsRadiant  *bool `json:"isRadiant"`
+	} `json:"players"`
+	RadiantTeam json.RawMessage `json:"radiant_team"`
+	DireTeam    json.RawMessage `json:"dire_team"`
+}
+
+// ReadJSONL читает матчи в формате OpenDota, по одному JSON-объекту на строку.
+// Строки без победителя или героев пропускаются, их число возвращается в skipped.
+func ReadJSONL(r io.Reader) (matches []Match, skipped int, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var raw openDotaMatch
+		if err := json.Unmarshal([]byte(text), &raw); err != nil {
+			return nil, skipped, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		match, ok := raw.toMatch()
+		if !ok {
+			skipped++
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, skipped, scanner.Err()
+}
+
+func (raw openDotaMatch) toMatch() (Match, bool) {
+	if raw.RadiantWin == nil {
+		return Match{}, false
+	}
+	m := Match{MatchID: raw.MatchID, RadiantWin: *raw.RadiantWin}
+
+	switch {
+	case len(raw.PicksBans) > 0:
+		for _, pb := range raw.PicksBans {
+			if !pb.IsPick {
+				continue
+			}
+			if pb.Team == 0 {
+				m.Radiant = append(m.Radiant, pb.HeroID)
+			} else {
+				m.Dire = append(m.Dire, pb.HeroID)
+			}
+		}
+	case len(raw.Players) > 0:
+		for _, p := range raw.Players {
+			// player_slot < 128 — Radiant, если isRadiant не указан.
+			radiant := p.PlayerSlot < 128
+			if p.IsRadiant != nil {
+				radiant = *p.IsRadiant
+			}
+			if radiant {
+				m.Radiant = append(m.Radiant, p.HeroID)
+			} else {
+				m.Dire = append(m.Dire, p.HeroID)
+			}
+		}
+	default:
+		m.Radiant = parseTeam(raw.RadiantTeam)
+		m.Dire = parseTeam(raw.DireTeam)
+	}
+
+	return m, validTeam(m.Radiant) && validTeam(m.Dire)
+}
+
+// parseTeam понимает и массив героев, и строку "1,2,3,4,5".
+func parseTeam(raw json.RawMessage) []int {
+	if len(raw) == 0 {
+		return nil
+	}
+	var ids []int
+	if err := json.Unmarshal(raw, &ids); err == nil {
+		return ids
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return nil
+	}
+	return splitHeroes(text)
+}
+
+// ReadCSV читает матчи из CSV с заголовком match_id,radiant_win,radiant_team,dire_team.
+// Герои команды перечисляются через запятую, точку с запятой или пробел.
+func ReadCSV(r io.Reader) (matches []Match, skipped int, err error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"radiant_win", "radiant_team", "dire_team"} {
+		if _, ok := columns[name]; !ok {
+			return nil, 0, fmt.Errorf("csv: missing column %q", name)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, skipped, err
+		}
+
+		radiantWin, err := strconv.ParseBool(strings.TrimSpace(record[columns["radiant_win"]]))
+		if err != nil {
+			skipped++
+			continue
+		}
+		m := Match{
+			RadiantWin: radiantWin,
+			Radiant:    splitHeroes(record[columns["radiant_team"]]),
+			Dire:       splitHeroes(record[columns["dire_team"]]),
+		}
+		if i, ok := columns["match_id"]; ok {
+			m.MatchID, _ = strconv.ParseInt(strings.TrimSpace(record[i]), 10, 64)
+		}
+		if !validTeam(m.Radiant) || !validTeam(m.Dire) {
+			skipped++
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches, skipped, nil
+}
+
+func splitHeroes(text string) []int {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '|'
+	})
+	ids := make([]int, 0, len(fields))
+	for _, f := range fields {
+		id, err := strconv.Atoi(f)
+		if err != nil {
+			return nil
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func validTeam(ids []int) bool {
+	if len(ids) == 0 || len(ids) > 5 {
+		return false
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadJSONL(t *testing.T) {
+	f, err := os.Open("testdata/matches.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	matches, skipped, err := ReadJSONL(f)
+	if err != nil {
+		t.Fatalf("ReadJSONL: %v", err)
+	}
+	want := []Match{
+		// picks_bans: баны пропускаются, team 0 — Radiant
+		{MatchID: 1, Radiant: []int{1, 3}, Dire: []int{2, 4}, RadiantWin: true},
+		// players: player_slot, а isRadiant важнее слота
+		{MatchID: 2, Radiant: []int{5}, Dire: []int{6, 7}},
+		// radiant_team массивом и dire_team строкой
+		{MatchID: 3, Radiant: []int{10, 11}, Dire: []int{12, 13}, RadiantWin: true},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Fatalf("matches %+v, want %+v", matches, want)
+	}
+	// Матч без победителя и матч с шестью героями
+	if skipped != 2 {
+		t.Fatalf("skipped %d, want 2", skipped)
+	}
+}
+
+func TestReadJSONLRejectsMalformedLine(t *testing.T) {
+	input := `{"match_id":1,"radiant_win":true,"radiant_team":[1],"dire_team":[2]}
+{"match_id":2,`
+	_, _, err := ReadJSONL(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("err = %v, want an error on line 2", err)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	f, err := os.Open("testdata/matches.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	matches, skipped, err := ReadCSV(f)
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	want := []Match{
+		{MatchID: 1, Radiant: []int{1, 2, 3}, Dire: []int{4, 5, 6}, RadiantWin: true},
+		{MatchID: 2, Radiant: []int{7, 8}, Dire: []int{9, 10}},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Fatalf("matches %+v, want %+v", matches, want)
+	}
+	// Непонятный победитель, герой 0 и пустая команда
+	if skipped != 3 {
+		t.Fatalf("skipped %d, want 3", skipped)
+	}
+}
+
+func TestReadCSVRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing column", "match_id,radiant_win,radiant_team\n1,true,1\n"},
+		{"wrong field count", "radiant_win,radiant_team,dire_team\ntrue,1,2\ntrue,1\n"},
+		{"unterminated quote", "radiant_win,radiant_team,dire_team\ntrue,\"1,2\n"},
+		{"empty input", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReadCSV(strings.NewReader(tt.input)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package analytics
+
+import "github.com/example/draftpractice/internal/draft"
+
+// PickImpact — как один пик сдвинул оценку драфта.
+type PickImpact struct {
+	Step                  int        `json:"step"`
+	Side                  draft.Side `json:"side"`
+	HeroID                int        `json:"heroId"`
+	RadiantWinProbability float64    `json:"radiantWinProbability"`
+	Delta                 float64    `json:"delta"`
+}
+
+// Evaluation — оценка сессии: итоговая после Completed, текущая во время драфта.
+type Evaluation struct {
+	SessionID             string       `json:"sessionId"`
+	Completed             bool         `json:"completed"`
+	Step                  int          `json:"step"`
+	RadiantWinProbability float64      `json:"radiantWinProbability"`
+	Timeline              []PickImpact `json:"timeline"`
+}
+
+// Evaluate проходит по сделанным ходам Order и считает оценку после каждого пика.
+func Evaluate(model *Model, session *draft.DraftSession) Evaluation {
+	result := Evaluation{
+		SessionID: session.ID,
+		Completed: session.Completed,
+		Step:      session.Step,
+		Timeline:  []PickImpact{},
+	}
+
+	var radiant, dire []int
+	prev := model.Predict(nil, nil)
+	for step := 0; step < session.Step && step < len(session.Order); step++ {
+		turn := session.Order[step]
+		if turn.Phase != draft.PhasePick {
+			continue
+		}
+
+		var hero int
+		if turn.Side == draft.SideRadiant {
+			if len(radiant) >= len(session.Radiant.Picks) {
+				continue
+			}
+			hero = session.Radiant.Picks[len(radiant)]
+			radiant = append(radiant, hero)
+		} else {
+			if len(dire) >= len(session.Dire.Picks) {
+				continue
+			}
+			hero = session.Dire.Picks[len(dire)]
+			dire = append(dire, hero)
+		}
+
+		p := model.Predict(radiant, dire)
+		result.Timeline = append(result.Timeline, PickImpact{
+			Step:                  step,
+			Side:                  turn.Side,
+			HeroID:                hero,
+			RadiantWinProbability: p,
+			Delta:                 p - prev,
+		})
+		prev = p
+	}
+
+	result.RadiantWinProbability = model.Predict(session.Radiant.Picks, session.Dire.Picks)
+	return result
+}
@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/example/draftpractice/internal/draft"
+)
+
+func TestEvaluateTimeline(t *testing.T) {
+	model := &Model{
+		Radiant: map[int]float64{1: 1, 3: 0.5},
+		Dire:    map[int]float64{2: -1},
+	}
+	// Драфт прерван перед последним пиком Dire: он не попадает в таймлайн.
+	session := &draft.DraftSession{
+		ID:   "s1",
+		Step: 4,
+		Order: []draft.Turn{
+			{Phase: draft.PhaseBan, Side: draft.SideRadiant},
+			{Phase: draft.PhasePick, Side: draft.SideRadiant},
+			{Phase: draft.PhasePick, Side: draft.SideDire},
+			{Phase: draft.PhasePick, Side: draft.SideRadiant},
+			{Phase: draft.PhasePick, Side: draft.SideDire},
+		},
+		Radiant: draft.Team{Bans: []int{9}, Picks: []int{1, 3}},
+		Dire:    draft.Team{Picks: []int{2}},
+	}
+
+	result := Evaluate(model, session)
+
+	sig := func(z float64) float64 { return 1 / (1 + math.Exp(-z)) }
+	want := []PickImpact{
+		{Step: 1, Side: draft.SideRadiant, HeroID: 1, RadiantWinProbability: sig(1), Delta: sig(1) - 0.5},
+		{Step: 2, Side: draft.SideDire, HeroID: 2, RadiantWinProbability: 0.5, Delta: 0.5 - sig(1)},
+		{Step: 3, Side: draft.SideRadiant, HeroID: 3, RadiantWinProbability: sig(0.5), Delta: sig(0.5) - 0.5},
+	}
+	if len(result.Timeline) != len(want) {
+		t.Fatalf("timeline %+v, want %d picks", result.Timeline, len(want))
+	}
+	for i, got := range result.Timeline {
+		w := want[i]
+		if got.Step != w.Step || got.Side != w.Side || got.HeroID != w.HeroID ||
+			!almostEqual(got.RadiantWinProbability, w.RadiantWinProbability) ||
+			!almostEqual(got.Delta, w.Delta) {
+			t.Errorf("timeline[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+	if result.SessionID != "s1" || result.Step != 4 || result.Completed {
+		t.Errorf("header: %+v", result)
+	}
+	if !almostEqual(result.RadiantWinProbability, sig(0.5)) {
+		t.Errorf("final probability %v, want %v", result.RadiantWinProbability, sig(0.5))
+	}
+}
+
+func TestEvaluateEmptySession(t *testing.T) {
+	result := Evaluate(NewModel(), &draft.DraftSession{ID: "s2"})
+	if result.Timeline == nil || len(result.Timeline) != 0 {
+		t.Fatalf("timeline %#v, want an empty slice", result.Timeline)
+	}
+	if result.RadiantWinProbability != 0.5 {
+		t.Fatalf("probability %v, want 0.5", result.RadiantWinProbability)
+	}
+}
@@ -0,0 +1,179 @@
+package analytics
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MatchupSource — матрица контрпиков и синергий (реализуется heroes.Matrix).
+type MatchupSource interface {
+	Advantage(heroID, enemyID int) float64
+	Synergy(heroID, allyID int) float64
+}
+
+// Model — логистическая регрессия вероятности победы Radiant.
+//
+// Признаки: one-hot героев каждой стороны, разница синергий внутри команд
+// и суммарное преимущество героев Radiant над героями Dire.
+type Model struct {
+	Bias    float64         `json:"bias"`
+	Radiant map[int]float64 `json:"radiant"`
+	Dire    map[int]float64 `json:"dire"`
+	Synergy float64         `json:"synergy"`
+	Counter float64         `json:"counter"`
+	Meta    ModelMeta       `json:"meta"`
+
+	matchups MatchupSource
+}
+
+// ModelMeta — информация об обучении модели.
+type ModelMeta struct {
+	TrainedAt time.Time `json:"trainedAt"`
+	Samples   int       `json:"samples"`
+	LogLoss   float64   `json:"logLoss"`
+	Accuracy  float64   `json:"accuracy"`
+}
+
+// Features — агрегированные признаки драфта.
+type Features struct {
+	Radiant []int
+	Dire    []int
+	Synergy float64
+	Counter float64
+}
+
+// NewModel создаёт пустую модель: без весов героев она опирается только
+// на матрицу матчапов, если та подключена.
+func NewModel() *Model {
+	return &Model{
+		Radiant: make(map[int]float64),
+		Dire:    make(map[int]float64),
+		Synergy: 4,
+		Counter: 4,
+	}
+}
+
+// Load читает веса модели из JSON или gob (по расширению .gob).
+func Load(path string) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// gob не передаёт нулевые значения, поэтому декодируем в пустую модель,
+	// а не в NewModel с весами по умолчанию.
+	model := &Model{}
+	if strings.EqualFold(filepath.Ext(path), ".gob") {
+		err = gob.NewDecoder(f).Decode(model)
+	} else {
+		err = json.NewDecoder(f).Decode(model)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode model %s: %w", path, err)
+	}
+	if model.Radiant == nil {
+		model.Radiant = make(map[int]float64)
+	}
+	if model.Dire == nil {
+		model.Dire = make(map[int]float64)
+	}
+	return model, nil
+}
+
+// Save записывает веса модели в JSON или gob (по расширению .gob). Запись
+// атомарна: модель пишется во временный файл рядом и переименовывается
+// поверх, так что draft-api никогда не прочитает недописанную модель.
+func (m *Model) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if strings.EqualFold(filepath.Ext(path), ".gob") {
+		err = gob.NewEncoder(tmp).Encode(m)
+	} else {
+		enc := json.NewEncoder(tmp)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(m)
+	}
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// WithMatchups подключает матрицу для признаков синергии и контрпиков.
+func (m *Model) WithMatchups(source MatchupSource) *Model {
+	m.matchups = source
+	return m
+}
+
+// Extract считает признаки драфта. Без матрицы агрегаты равны нулю.
+func Extract(source MatchupSource, radiant, dire []int) Features {
+	f := Features{Radiant: radiant, Dire: dire}
+	if source == nil {
+		return f
+	}
+
+	f.Synergy = teamSynergy(source, radiant) - teamSynergy(source, dire)
+	for _, r := range radiant {
+		for _, d := range dire {
+			f.Counter += source.Advantage(r, d)
+		}
+	}
+	return f
+}
+
+func teamSynergy(source MatchupSource, team []int) float64 {
+	total := 0.0
+	for i, a := range team {
+		for _, b := range team[i+1:] {
+			total += source.Synergy(a, b)
+		}
+	}
+	return total
+}
+
+// Score — линейная часть модели (логит).
+func (m *Model) Score(f Features) float64 {
+	z := m.Bias + m.Synergy*f.Synergy + m.Counter*f.Counter
+	for _, h := range f.Radiant {
+		z += m.Radiant[h]
+	}
+	for _, h := range f.Dire {
+		z += m.Dire[h]
+	}
+	return z
+}
+
+// Predict возвращает вероятность победы Radiant. Подходит как draft.Evaluator;
+// работает и для неполных составов — тогда это промежуточная оценка.
+func (m *Model) Predict(radiant, dire []int) float64 {
+	return sigmoid(m.Score(Extract(m.matchups, radiant, dire)))
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
@@ -0,0 +1,146 @@
+package analytics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fixedMatchups — крошечная матрица матчапов; отсутствующие пары равны нулю.
+type fixedMatchups struct {
+	advantage map[[2]int]float64
+	synergy   map[[2]int]float64
+}
+
+func (m fixedMatchups) Advantage(heroID, enemyID int) float64 {
+	return m.advantage[[2]int{heroID, enemyID}]
+}
+
+func (m fixedMatchups) Synergy(heroID, allyID int) float64 {
+	return m.synergy[[2]int{heroID, allyID}]
+}
+
+var testMatchups = fixedMatchups{
+	advantage: map[[2]int]float64{{1, 2}: 0.1},
+	synergy:   map[[2]int]float64{{1, 3}: 0.2, {2, 4}: 0.05},
+}
+
+func testModel() *Model {
+	return &Model{
+		Bias:    0.5,
+		Radiant: map[int]float64{1: 1},
+		Dire:    map[int]float64{2: -0.5},
+		Synergy: 2,
+		Counter: 3,
+	}
+}
+
+func TestExtract(t *testing.T) {
+	f := Extract(testMatchups, []int{1, 3}, []int{2, 4})
+	if !almostEqual(f.Synergy, 0.15) || !almostEqual(f.Counter, 0.1) {
+		t.Fatalf("features: synergy %v, counter %v, want 0.15 and 0.1", f.Synergy, f.Counter)
+	}
+	if f = Extract(nil, []int{1, 3}, []int{2, 4}); f.Synergy != 0 || f.Counter != 0 {
+		t.Fatalf("features without matchups: synergy %v, counter %v", f.Synergy, f.Counter)
+	}
+}
+
+func TestPredict(t *testing.T) {
+	tests := []struct {
+		name     string
+		matchups MatchupSource
+		radiant  []int
+		dire     []int
+		logit    float64
+	}{
+		{"empty draft", testMatchups, nil, nil, 0.5},
+		{"heroes and counter", testMatchups, []int{1}, []int{2}, 0.5 + 1 - 0.5 + 3*0.1},
+		{"synergy", testMatchups, []int{1, 3}, []int{2, 4}, 0.5 + 1 - 0.5 + 2*0.15 + 3*0.1},
+		{"without matchups", nil, []int{1, 3}, []int{2, 4}, 0.5 + 1 - 0.5},
+		{"unknown heroes", testMatchups, []int{7}, []int{8}, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := testModel().WithMatchups(tt.matchups)
+			want := 1 / (1 + math.Exp(-tt.logit))
+			if got := model.Predict(tt.radiant, tt.dire); !almostEqual(got, want) {
+				t.Fatalf("Predict = %v, want %v (logit %v)", got, want, tt.logit)
+			}
+		})
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	model := testModel()
+	// Нулевой вес не должен превратиться в значение по умолчанию NewModel.
+	model.Counter = 0
+	model.Meta = ModelMeta{
+		TrainedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Samples:   42,
+		LogLoss:   0.61,
+		Accuracy:  0.7,
+	}
+
+	for _, name := range []string{"model.json", "model.gob"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, name)
+			if err := model.Save(path); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if !reflect.DeepEqual(loaded, model) {
+				t.Fatalf("loaded %+v, want %+v", loaded, model)
+			}
+			assertOnlyFile(t, dir, name)
+		})
+	}
+}
+
+func TestSaveKeepsPreviousModelOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "model.json")
+	if err := testModel().Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	broken := testModel()
+	broken.Bias = math.NaN() // JSON не кодирует NaN
+	if err := broken.Save(path); err == nil {
+		t.Fatal("expected an error for a NaN weight")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load after failed save: %v", err)
+	}
+	if loaded.Bias != testModel().Bias {
+		t.Fatalf("bias %v, want the previous model", loaded.Bias)
+	}
+	assertOnlyFile(t, dir, "model.json")
+}
+
+func assertOnlyFile(t *testing.T, dir, name string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != name {
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.Name()
+		}
+		t.Fatalf("files in dir: %v, want only %s", names, name)
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// TrainConfig — параметры обучения логистической регрессии.
+type TrainConfig struct {
+	Epochs       int
+	LearningRate float64
+	L2           float64
+	// Holdout — доля матчей, отложенных для оценки, в диапазоне [0, 1)
+	// (0 — без отложенной выборки).
+	Holdout float64
+	Seed    int64
+	// Matchups — матрица для признаков синергии и контрпиков; nil — только герои.
+	Matchups MatchupSource
+}
+
+// Metrics — качество модели на выборке.
+type Metrics struct {
+	Samples  int     `json:"samples"`
+	LogLoss  float64 `json:"logLoss"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// Train обучает модель стохастическим градиентным спуском и возвращает
+// метрики на обучающей и отложенной выборках.
+func Train(matches []Match, cfg TrainConfig) (model *Model, train, holdout Metrics, err error) {
+	if len(matches) == 0 {
+		return nil, train, holdout, errors.New("no matches to train on")
+	}
+	// Отрицательная доля дала бы split больше длины выборки, а доля >= 1 —
+	// пустую обучающую выборку; NaN отсекается тем же сравнением.
+	if !(cfg.Holdout >= 0 && cfg.Holdout < 1) {
+		return nil, train, holdout, fmt.Errorf("holdout must be in [0, 1), got %v", cfg.Holdout)
+	}
+	if cfg.Epochs <= 0 {
+		cfg.Epochs = 30
+	}
+	if cfg.LearningRate <= 0 {
+		cfg.LearningRate = 0.05
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	shuffled := append([]Match(nil), matches...)
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	split := len(shuffled) - int(float64(len(shuffled))*cfg.Holdout)
+	trainSet, holdoutSet := shuffled[:split], shuffled[split:]
+	if len(trainSet) == 0 {
+		return nil, train, holdout, errors.New("holdout leaves no matches to train on")
+	}
+
+	features := func(set []Match) []Features {
+		result := make([]Features, len(set))
+		for i, m := range set {
+			result[i] = Extract(cfg.Matchups, m.Radiant, m.Dire)
+		}
+		return result
+	}
+	trainX, holdoutX := features(trainSet), features(holdoutSet)
+
+	model = NewModel()
+	model.Synergy, model.Counter = 0, 0
+	model.matchups = cfg.Matchups
+
+	order := make([]int, len(trainSet))
+	for i := range order {
+		order[i] = i
+	}
+
+	for epoch := 0; epoch < cfg.Epochs; epoch++ {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		// Шаг плавно уменьшается, чтобы веса сходились к концу обучения.
+		lr := cfg.LearningRate / (1 + float64(epoch)/10)
+
+		for _, i := range order {
+			x := trainX[i]
+			grad := sigmoid(model.Score(x)) - label(trainSet[i])
+
+			model.Bias -= lr * grad
+			model.Synergy -= lr * grad * x.Synergy
+			model.Counter -= lr * grad * x.Counter
+			for _, h := range x.Radiant {
+				model.Radiant[h] -= lr * (grad + cfg.L2*model.Radiant[h])
+			}
+			for _, h := range x.Dire {
+				model.Dire[h] -= lr * (grad + cfg.L2*model.Dire[h])
+			}
+		}
+	}
+
+	train = evaluate(model, trainSet, trainX)
+	holdout = evaluate(model, holdoutSet, holdoutX)
+	model.Meta = ModelMeta{
+		TrainedAt: time.Now().UTC(),
+		Samples:   len(trainSet),
+		LogLoss:   holdout.LogLoss,
+		Accuracy:  holdout.Accuracy,
+	}
+	if holdout.Samples == 0 {
+		model.Meta.LogLoss, model.Meta.Accuracy = train.LogLoss, train.Accuracy
+	}
+	return model, train, holdout, nil
+}
+
+func evaluate(model *Model, set []Match, x []Features) Metrics {
+	metrics := Metrics{Samples: len(set)}
+	if len(set) == 0 {
+		return metrics
+	}
+
+	const eps = 1e-12
+	correct := 0
+	for i, m := range set {
+		p := sigmoid(model.Score(x[i]))
+		y := label(m)
+		metrics.LogLoss -= y*math.Log(math.Max(p, eps)) + (1-y)*math.Log(math.Max(1-p, eps))
+		if (p >= 0.5) == m.RadiantWin {
+			correct++
+		}
+	}
+	metrics.LogLoss /= float64(len(set))
+	metrics.Accuracy = float64(correct) / float64(len(set))
+	return metrics
+}
+
+func label(m Match) float64 {
+	if m.RadiantWin {
+		return 1
+	}
+	return 0
+}
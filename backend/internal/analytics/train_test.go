@@ -0,0 +1,92 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+// separableMatches — Radiant с героем 1 всегда выигрывает, с героем 2 —
+// всегда проигрывает; соперник одинаковый.
+func separableMatches(n int) []Match {
+	matches := make([]Match, 0, 2*n)
+	for i := 0; i < n; i++ {
+		matches = append(matches,
+			Match{Radiant: []int{1}, Dire: []int{3}, RadiantWin: true},
+			Match{Radiant: []int{2}, Dire: []int{3}, RadiantWin: false},
+		)
+	}
+	return matches
+}
+
+func TestTrainLowersLoss(t *testing.T) {
+	matches := separableMatches(20)
+
+	short, shortMetrics, _, err := Train(matches, TrainConfig{Epochs: 1, Seed: 1})
+	if err != nil {
+		t.Fatalf("train 1 epoch: %v", err)
+	}
+	model, metrics, holdout, err := Train(matches, TrainConfig{Epochs: 50, LearningRate: 0.2, Seed: 1})
+	if err != nil {
+		t.Fatalf("train 50 epochs: %v", err)
+	}
+
+	// Нулевая модель предсказывает 0.5 и даёт log loss ln 2.
+	if shortMetrics.LogLoss >= math.Ln2 {
+		t.Errorf("loss after 1 epoch %v, want below ln 2", shortMetrics.LogLoss)
+	}
+	if metrics.LogLoss >= shortMetrics.LogLoss {
+		t.Errorf("loss after 50 epochs %v, want below %v", metrics.LogLoss, shortMetrics.LogLoss)
+	}
+	if metrics.Accuracy != 1 || metrics.Samples != len(matches) || holdout.Samples != 0 {
+		t.Errorf("metrics %+v, holdout %+v", metrics, holdout)
+	}
+	if model.Radiant[1] <= 0 || model.Radiant[2] >= 0 {
+		t.Errorf("weights: hero 1 %v, hero 2 %v", model.Radiant[1], model.Radiant[2])
+	}
+	if model.Predict([]int{1}, []int{3}) <= short.Predict([]int{1}, []int{3}) {
+		t.Error("more epochs did not increase the winning lineup's probability")
+	}
+	// Без отложенной выборки мета-данные берутся с обучающей
+	if model.Meta.Samples != len(matches) || model.Meta.LogLoss != metrics.LogLoss {
+		t.Errorf("meta %+v", model.Meta)
+	}
+}
+
+func TestTrainHoldout(t *testing.T) {
+	matches := separableMatches(20)
+	tests := []struct {
+		holdout float64
+		ok      bool
+		samples int
+	}{
+		{0, true, 0},
+		{0.25, true, 10},
+		{0.999, true, 39},
+		{-0.1, false, 0},
+		{1, false, 0},
+		{1.5, false, 0},
+		{math.NaN(), false, 0},
+	}
+	for _, tt := range tests {
+		_, train, holdout, err := Train(matches, TrainConfig{Epochs: 1, Holdout: tt.holdout})
+		if !tt.ok {
+			if err == nil {
+				t.Errorf("holdout %v: expected an error", tt.holdout)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("holdout %v: %v", tt.holdout, err)
+			continue
+		}
+		if holdout.Samples != tt.samples || train.Samples != len(matches)-tt.samples {
+			t.Errorf("holdout %v: train %d, holdout %d samples", tt.holdout, train.Samples, holdout.Samples)
+		}
+	}
+}
+
+func TestTrainWithoutMatches(t *testing.T) {
+	if _, _, _, err := Train(nil, TrainConfig{}); err == nil {
+		t.Fatal("expected an error for an empty dataset")
+	}
+}
@@ -0,0 +1,163 @@
+package draft
+
+import (
+	"errors"
+	"fmt"
+)
+
+// truncated собирает сессию заново из первых step ходов журнала.
+func (s *DraftSession) truncated(step int) (*DraftSession, error) {
+	base := s.Clone()
+	base.Events = base.Events[:base.actionIndex(step)]
+	return base.Rebuild()
+}
+
+// Undo откатывает последние count ходов тренировочной сессии. Пока лобби
+// пусто, откат открыт; иначе его делает тренер или капитан, но капитан — только
+// если среди откатываемых ходов нет ходов другой стороны-человека (ходы бота
+// откатывать можно). Завершённый драфт после отката продолжается: таймер
+// и бот запускаются снова.
+func (s *Store) Undo(id, token string, count int) (*DraftSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if !session.Practice {
+		return nil, fmt.Errorf("%w: undo is allowed only in practice sessions", ErrForbidden)
+	}
+	actions := session.Actions()
+	if len(actions) == 0 {
+		return nil, errors.New("no actions to undo")
+	}
+	if count < 1 || count > len(actions) {
+		return nil, fmt.Errorf("can undo from 1 to %d actions", len(actions))
+	}
+	if err := session.authorizeUndo(token, actions[len(actions)-count:]); err != nil {
+		return nil, err
+	}
+
+	rebuilt, err := session.truncated(len(actions) - count)
+	if err != nil {
+		return nil, err
+	}
+	// Пауза продолжается и после отката: её запись остаётся в журнале,
+	// чтобы к ней нашлось снятие.
+	if session.Pause != nil {
+		for i := len(session.Events) - 1; i >= 0; i-- {
+			if e := session.Events[i]; e.Type == EventPaused {
+				e.Step, e.Phase = rebuilt.Step, rebuilt.Stage
+				rebuilt.Events = append(rebuilt.Events, e)
+				break
+			}
+		}
+	}
+
+	// Переписываем сессию на месте: на неё ссылаются таймер и горутины бота.
+	rebuilt.bot = session.bot
+	rebuilt.clock = s.clock
+	rebuilt.catalog = s.catalog
+	rebuilt.Pause = session.Pause
+	rebuilt.ticking = session.ticking
+	rebuilt.revision = session.revision + 1
+	// Ход после отката начинается с полного таймера (на паузе — от её начала)
+	if rebuilt.Pause != nil {
+		rebuilt.startTurn(rebuilt.Pause.Since)
+	} else {
+		rebuilt.startTurn(s.clock.Now())
+	}
+	*session = *rebuilt
+
+	fmt.Printf("[UNDO] Draft %s rolled back %d actions, now %s %s at step %d\n",
+		id, count, session.Side, session.Stage, session.Step)
+
+	s.persist(session)
+	s.publish(session, EventUndo)
+	s.startTimer(session)
+	s.scheduleBotMove(session)
+	return s.view(session), nil
+}
+
+// authorizeUndo проверяет, может ли владелец токена откатить ходы undone.
+func (s *DraftSession) authorizeUndo(token string, undone []DraftEvent) error {
+	if len(s.Lobby) == 0 {
+		return nil
+	}
+	member, ok := s.member(token)
+	if ok && member.Role == LobbyCoach {
+		return nil
+	}
+	if !ok || member.Role != LobbyCaptain {
+		return fmt.Errorf("%w: only captains and coaches can undo actions", ErrForbidden)
+	}
+	for _, event := range undone {
+		if event.Side != member.Side && event.Side != s.BotSide {
+			return fmt.Errorf("%w: step %d was made by %s, only its captain or a coach can undo it",
+				ErrForbidden, event.Step, event.Side)
+		}
+	}
+	return nil
+}
+
+// Fork создаёт новую тренировочную сессию из состояния session на шаге step
+// (после первых step ходов): те же команды, формат, пул и бот, но своё лобби
+// и таймер. Ответвиться можно и от завершённой сессии.
+func (s *Store) Fork(id string, step int) (*DraftSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, ok := s.sessions[id]
+	if !ok {
+		record, err := s.repo.Load(id)
+		if err != nil {
+			return nil, ErrSessionNotFound
+		}
+		source = record.restore()
+		source.catalog = s.catalog
+		if err := source.pinPatch(); err != nil {
+			return nil, err
+		}
+	}
+	if actions := len(source.Actions()); step < 0 || step > actions {
+		return nil, fmt.Errorf("step must be between 0 and %d", actions)
+	}
+	if step == len(source.Order) {
+		return nil, fmt.Errorf("step %d is the end of the draft", step)
+	}
+
+	factory, ok := s.bots[source.BotType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownBot, source.BotType)
+	}
+
+	forked, err := source.truncated(step)
+	if err != nil {
+		return nil, err
+	}
+	forked.ID = generateID()
+	forked.Lobby = nil
+	// Ответвление — новая сессия, и тренером в ней будет тот, кто её создал
+	forked.CoachKey = generateID() + generateID()
+	forked.PauseRadiant = forked.PauseBudget
+	forked.PauseDire = forked.PauseBudget
+	forked.PauseCoach = forked.PauseBudget
+	forked.Practice = true
+	forked.ForkedFrom = id
+	forked.ForkStep = step
+	forked.bot = factory()
+	forked.clock = s.clock
+	forked.catalog = s.catalog
+	forked.startTurn(s.clock.Now())
+
+	s.sessions[forked.ID] = forked
+	s.persist(forked)
+
+	fmt.Printf("[SESSION] Draft %s forked from %s at step %d: %s %s\n",
+		forked.ID, id, step, forked.Side, forked.Stage)
+
+	s.startTimer(forked)
+	s.scheduleBotMove(forked)
+	return s.view(forked), nil
+}
@@ -0,0 +1,81 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// practiceLobby — тренировка двух команд людей с капитанами и тренером,
+// в которой Radiant и Dire уже сделали по ходу.
+func practiceLobby(t *testing.T) (store *Store, session *DraftSession, radiant, dire, coach LobbyMember) {
+	t.Helper()
+	store = NewStoreWithConfig(StoreConfig{Clock: NewFakeClock(time.Unix(0, 0))})
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+		Practice:    true,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	radiant = join(t, store, session.ID, "Miracle", LobbyCaptain, SideRadiant)
+	dire = join(t, store, session.ID, "Collapse", LobbyCaptain, SideDire)
+	coach = joinCoach(t, store, session, "Puppey")
+
+	for _, captain := range []LobbyMember{radiant, dire} {
+		if session, err = store.GetSession(session.ID); err != nil {
+			t.Fatalf("get session: %v", err)
+		}
+		if captain.Side != session.Side {
+			t.Fatalf("expected %s to act, got %s", captain.Side, session.Side)
+		}
+		if err := act(store, session, captain.Token); err != nil {
+			t.Fatalf("%s action: %v", captain.Side, err)
+		}
+	}
+	return store, session, radiant, dire, coach
+}
+
+func TestUndoOwnActionOnly(t *testing.T) {
+	store, session, radiant, dire, _ := practiceLobby(t)
+
+	// Последний ход сделал Dire: капитан Radiant откатить его не может.
+	if _, err := store.Undo(session.ID, radiant.Token, 1); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("radiant undoing a dire action: err = %v, want ErrForbidden", err)
+	}
+	undone, err := store.Undo(session.ID, dire.Token, 1)
+	if err != nil {
+		t.Fatalf("dire undoing its own action: %v", err)
+	}
+	if len(undone.Events) != 1 || undone.Side != SideDire {
+		t.Fatalf("after undo: %d events, %s to act", len(undone.Events), undone.Side)
+	}
+	if _, err := store.Undo(session.ID, dire.Token, 1); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("dire undoing a radiant action: err = %v, want ErrForbidden", err)
+	}
+}
+
+func TestUndoByCoach(t *testing.T) {
+	store, session, _, _, coach := practiceLobby(t)
+
+	undone, err := store.Undo(session.ID, coach.Token, 2)
+	if err != nil {
+		t.Fatalf("coach undo: %v", err)
+	}
+	if len(undone.Events) != 0 || undone.Side != SideRadiant {
+		t.Fatalf("after undo: %d events, %s to act", len(undone.Events), undone.Side)
+	}
+}
+
+func TestUndoRequiresPractice(t *testing.T) {
+	store, session := newHumanSession(t)
+	if err := act(store, session, ""); err != nil {
+		t.Fatalf("action: %v", err)
+	}
+	if _, err := store.Undo(session.ID, "", 1); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("undo outside practice: err = %v, want ErrForbidden", err)
+	}
+}
@@ -0,0 +1,99 @@
+package draft
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock — источник времени для таймеров сессий и задержек бота. В тестах
+// вместо системных часов подставляется FakeClock.
+type Clock interface {
+	Now() time.Time
+	// After срабатывает, когда по этим часам пройдёт d.
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock — Clock на системном времени.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock возвращает Clock на системном времени.
+func SystemClock() Clock { return systemClock{} }
+
+// FakeClock — управляемые вручную часы для детерминированных тестов:
+// время стоит, пока его не сдвинут Advance.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFakeClock создаёт FakeClock, показывающие start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now возвращает текущее время часов.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After срабатывает, когда Advance сдвинет часы как минимум на d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance сдвигает часы на d и будит всех, чьё время наступило,
+// в порядке их сроков.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	sort.Slice(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// Waiters возвращает число ожидающих After — по нему тест понимает,
+// что таймеры и боты дошли до ожидания.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil ждёт (в реальном времени), пока на часах не окажется
+// хотя бы n ожидающих.
+func (c *FakeClock) BlockUntil(n int) {
+	for c.Waiters() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,117 @@
+package draft
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// MatchupSource — данные о преимуществе героев друг против друга и синергии.
+// Реализуется heroes.Matrix.
+type MatchupSource interface {
+	Advantage(heroID, enemyID int) float64
+	Synergy(heroID, allyID int) float64
+}
+
+// counterCandidates — из скольких лучших вариантов бот семплирует ход.
+const counterCandidates = 8
+
+// CounterBot — бот, который пикает контрпики к составу соперника с учётом
+// синергии со своими героями, а в фазах банов убирает лучшие контрпики
+// к собственному составу.
+type CounterBot struct {
+	Matchups MatchupSource
+	// Temperature управляет случайностью: 0 — всегда лучший вариант,
+	// чем больше, тем чаще бот выбирает не топ-1 (в единицах преимущества,
+	// разумные значения 0.01–0.05).
+	Temperature float64
+}
+
+// NewCounterBot создаёт CounterBot поверх матрицы матчапов.
+func NewCounterBot(matchups MatchupSource, temperature float64) *CounterBot {
+	return &CounterBot{Matchups: matchups, Temperature: temperature}
+}
+
+type scoredHero struct {
+	id    int
+	score float64
+}
+
+// ChooseHero выбирает героя для текущего хода сессии.
+func (b *CounterBot) ChooseHero(session *DraftSession) int {
+	if b.Matchups == nil {
+		return 0
+	}
+
+	own := session.activeTeam().Picks
+	enemy := session.opponentTeam().Picks
+
+	candidates := make([]scoredHero, 0)
+	for _, h := range session.AvailableHeroes() {
+		if session.Stage == PhaseBan && session.IsLocked(opposite(session.Side), h.ID) {
+			continue
+		}
+
+		var score float64
+		if session.Stage == PhaseBan {
+			// Насколько герой был бы хорош для соперника.
+			score = b.lineupValue(h.ID, enemy, own)
+		} else {
+			score = b.lineupValue(h.ID, own, enemy)
+		}
+		candidates = append(candidates, scoredHero{id: h.ID, score: score})
+	}
+	if len(candidates) == 0 {
+		return 0
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > counterCandidates {
+		candidates = candidates[:counterCandidates]
+	}
+
+	choice := sampleSoftmax(candidates, b.Temperature)
+	fmt.Printf("[BOT] %s counter-%s hero %d (score %+.3f)\n",
+		session.Side, session.Stage, choice.id, choice.score)
+	return choice.id
+}
+
+// lineupValue — суммарное преимущество героя против enemies и синергия с allies.
+func (b *CounterBot) lineupValue(heroID int, allies, enemies []int) float64 {
+	value := 0.0
+	for _, e := range enemies {
+		value += b.Matchups.Advantage(heroID, e)
+	}
+	for _, a := range allies {
+		value += b.Matchups.Synergy(heroID, a)
+	}
+	return value
+}
+
+// sampleSoftmax выбирает кандидата с вероятностью exp(score/T).
+// Кандидаты должны быть отсортированы по убыванию score.
+func sampleSoftmax(candidates []scoredHero, temperature float64) scoredHero {
+	if temperature <= 0 || len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	top := candidates[0].score
+	weights := make([]float64, len(candidates))
+	total := 0.0
+	for i, c := range candidates {
+		weights[i] = math.Exp((c.score - top) / temperature)
+		total += weights[i]
+	}
+
+	r := rand.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r <= 0 {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
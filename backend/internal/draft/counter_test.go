@@ -0,0 +1,58 @@
+package draft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+// counterSession — сессия на пуле героев из тестовой матрицы: Radiant уже
+// взял Lion (26), Dire — Anti-Mage (1) и Faceless Void (41).
+func counterSession(t *testing.T, stage Phase) *DraftSession {
+	t.Helper()
+	session := newDraftSession("counter", "Radiant", "Dire", SideRadiant, formats[DefaultFormat], time.Now())
+	session.catalog = heroes.SnapshotCatalog()
+	session.Pool = []int{1, 2, 5, 8, 11, 14, 26, 41, 74, 86}
+	session.Radiant.Picks = []int{26}
+	session.Dire.Picks = []int{1, 41}
+	for _, id := range []int{26, 1, 41} {
+		session.taken[id] = struct{}{}
+	}
+	session.Stage = stage
+	session.Side = SideRadiant
+	return session
+}
+
+func loadFixture(t *testing.T) *heroes.Matrix {
+	t.Helper()
+	matrix, err := heroes.LoadMatchupFile("../heroes/testdata/matchups.json")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	return matrix
+}
+
+func TestCounterBotPicksBestCounter(t *testing.T) {
+	bot := NewCounterBot(loadFixture(t), 0)
+
+	// Pudge (14) лучше всех играет против AM и Void вместе с Lion.
+	if got := bot.ChooseHero(counterSession(t, PhasePick)); got != 14 {
+		t.Fatalf("pick = %d, want 14", got)
+	}
+}
+
+func TestCounterBotBansOpponentsBestHero(t *testing.T) {
+	bot := NewCounterBot(loadFixture(t), 0)
+
+	// В бан уходит герой, который сильнее всего дополнил бы Dire против Lion.
+	if got := bot.ChooseHero(counterSession(t, PhaseBan)); got != 2 {
+		t.Fatalf("ban = %d, want 2", got)
+	}
+}
+
+func TestCounterBotWithoutMatchups(t *testing.T) {
+	if got := NewCounterBot(nil, 0).ChooseHero(counterSession(t, PhasePick)); got != 0 {
+		t.Fatalf("bot without a matrix chose %d, want 0", got)
+	}
+}
@@ -0,0 +1,178 @@
+package draft
+
+import (
+	"fmt"
+	"time"
+)
+
+// DraftEvent — запись журнала сессии об одном ходе, паузе или её снятии.
+// Журнал только дописывается; по нему сессию можно собрать заново (Rebuild)
+// и проиграть с исходными интервалами (NewReplay).
+type DraftEvent struct {
+	// Тип записи: пустой — ход, EventPaused и EventResumed — пауза и её
+	// снятие (Side — сторона, поставившая паузу; пустая — пауза тренера)
+	Type   EventType    `json:"type,omitempty"`
+	Step   int          `json:"step"`
+	Side   Side         `json:"side"`
+	Phase  Phase        `json:"phase"`
+	HeroID int          `json:"heroId"`
+	Source ActionSource `json:"source"`
+	// Имя капитана из лобби, если ход сделал он
+	Actor string    `json:"actor,omitempty"`
+	At    time.Time `json:"at"`
+	// Секунды (с округлением вверх) и миллисекунды, оставшиеся на основном
+	// таймере хода и в резерве стороны
+	Timer     int   `json:"timer"`
+	Reserve   int   `json:"reserve"`
+	TimerMs   int64 `json:"timerMs"`
+	ReserveMs int64 `json:"reserveMs"`
+	// Сколько резерва сторона потратила на этот ход
+	ReserveUsed int `json:"reserveUsed"`
+}
+
+// record применяет ход текущей стороны и дописывает его в журнал.
+func (s *DraftSession) record(heroID int, source ActionSource, actor string) (DraftEvent, error) {
+	now := s.now()
+	s.refresh(now)
+	event := DraftEvent{
+		Step:      s.Step,
+		Side:      s.Side,
+		Phase:     s.Stage,
+		HeroID:    heroID,
+		Source:    source,
+		Actor:     actor,
+		At:        now.UTC(),
+		Timer:     s.CurrentTimer,
+		Reserve:   *s.reserve(s.Side),
+		TimerMs:   s.TimerMs,
+		ReserveMs: s.reserveMs(s.Side),
+	}
+	event.ReserveUsed = ceilSeconds(*s.bank(s.Side)) - event.Reserve
+
+	if err := s.ApplyAction(heroID); err != nil {
+		return DraftEvent{}, err
+	}
+	s.Events = append(s.Events, event)
+	return event, nil
+}
+
+// recordPause дописывает в журнал постановку или снятие паузы в момент at.
+func (s *DraftSession) recordPause(eventType EventType, by Side, actor string, at time.Time) {
+	s.Events = append(s.Events, DraftEvent{
+		Type:  eventType,
+		Step:  s.Step,
+		Side:  by,
+		Phase: s.Stage,
+		Actor: actor,
+		At:    at.UTC(),
+	})
+}
+
+// isAction — запись о ходе, а не о паузе.
+func (e DraftEvent) isAction() bool {
+	return e.Type == ""
+}
+
+// Actions возвращает ходы из журнала, без записей о паузах.
+func (s *DraftSession) Actions() []DraftEvent {
+	actions := make([]DraftEvent, 0, len(s.Events))
+	for _, e := range s.Events {
+		if e.isAction() {
+			actions = append(actions, e)
+		}
+	}
+	return actions
+}
+
+// actionIndex — длина журнала сразу после step-го хода (0 — до первого хода).
+// Паузы после этого хода в неё не входят.
+func (s *DraftSession) actionIndex(step int) int {
+	if step <= 0 {
+		return 0
+	}
+	seen := 0
+	for i, e := range s.Events {
+		if !e.isAction() {
+			continue
+		}
+		if seen++; seen == step {
+			return i + 1
+		}
+	}
+	return len(s.Events)
+}
+
+// reserveMs — оставшийся резерв стороны в миллисекундах на момент refresh.
+func (s *DraftSession) reserveMs(side Side) int64 {
+	if side == SideRadiant {
+		return s.ReserveRadiantMs
+	}
+	return s.ReserveDireMs
+}
+
+// Rebuild собирает сессию заново из её журнала: создаёт пустую сессию того же
+// формата и по порядку переигрывает события. Состояние таймера соответствует
+// началу хода, следующего за последним событием.
+func (s *DraftSession) Rebuild() (*DraftSession, error) {
+	format, ok := formats[s.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, s.Format)
+	}
+
+	rebuilt := s.blank(format)
+	rebuilt.BotSpeed = s.BotSpeed
+	rebuilt.BotSide = s.BotSide
+	rebuilt.BotType = s.BotType
+	rebuilt.Lobby = append([]LobbyMember(nil), s.Lobby...)
+	rebuilt.Practice = s.Practice
+	rebuilt.CoachKey = s.CoachKey
+	rebuilt.PauseBudget = s.PauseBudget
+	rebuilt.PauseRadiant = s.PauseRadiant
+	rebuilt.PauseDire = s.PauseDire
+	rebuilt.PauseCoach = s.PauseCoach
+	rebuilt.ForkedFrom = s.ForkedFrom
+	rebuilt.ForkStep = s.ForkStep
+
+	for _, e := range s.Events {
+		if err := rebuilt.replay(e); err != nil {
+			return nil, err
+		}
+	}
+	return rebuilt, nil
+}
+
+// blank — пустая сессия с теми же командами, форматом, пулом и запретами.
+func (s *DraftSession) blank(format Format) *DraftSession {
+	blank := newDraftSession(s.ID, s.Radiant.Name, s.Dire.Name, s.FirstPick, format, s.now())
+	blank.clock = s.clock
+	blank.catalog = s.catalog
+	blank.patch = s.patch
+	blank.Patch = s.Patch
+	blank.CreatedAt = s.CreatedAt
+	blank.Pool = append([]int(nil), s.Pool...)
+	blank.Locked = cloneLocked(s.Locked)
+	return blank
+}
+
+// replay применяет событие журнала, проверяя, что оно соответствует порядку ходов.
+// Записи о паузах на состояние драфта не влияют и только переносятся в журнал.
+func (s *DraftSession) replay(e DraftEvent) error {
+	if !e.isAction() {
+		s.Events = append(s.Events, e)
+		return nil
+	}
+	if e.Step != s.Step || e.Side != s.Side || e.Phase != s.Stage {
+		return fmt.Errorf("event for step %d (%s %s) does not match the schedule (%s %s at step %d)",
+			e.Step, e.Side, e.Phase, s.Side, s.Stage, s.Step)
+	}
+	if err := s.ApplyAction(e.HeroID); err != nil {
+		return fmt.Errorf("replay step %d: %w", e.Step, err)
+	}
+	*s.bank(e.Side) = time.Duration(e.ReserveMs) * time.Millisecond
+	if e.ReserveMs == 0 {
+		*s.bank(e.Side) = time.Duration(e.Reserve) * time.Second
+	}
+	s.refresh(s.now())
+	s.Events = append(s.Events, e)
+	return nil
+}
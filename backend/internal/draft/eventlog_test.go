@@ -0,0 +1,112 @@
+package draft
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// playDraft ходит за обе стороны, тратя на ходы разное время: часть ходов
+// уходит в резерв с точностью до миллисекунд. Перед ходом pauseAt ставится
+// пауза на 10 секунд. Останавливается после steps ходов или в конце драфта.
+func playDraft(t *testing.T, store *Store, clock *FakeClock, id string, steps, pauseAt int) *DraftSession {
+	t.Helper()
+	for step := 0; step < steps; step++ {
+		session := getSession(t, store, id)
+		if session.Completed {
+			return session
+		}
+		timer := time.Duration(session.Order[session.Step].Timer) * time.Second
+		think := []time.Duration{
+			3250 * time.Millisecond,
+			timer + 2500*time.Millisecond,
+			100 * time.Millisecond,
+			timer + 7125*time.Millisecond,
+		}[step%4]
+
+		if step == pauseAt {
+			runFor(clock, think/2)
+			if _, err := store.Pause(id, ""); err != nil {
+				t.Fatalf("pause: %v", err)
+			}
+			clock.Advance(10 * time.Second)
+			if _, err := store.Resume(id, ""); err != nil {
+				t.Fatalf("resume: %v", err)
+			}
+			think -= think / 2
+		}
+		runFor(clock, think)
+		if err := act(store, getSession(t, store, id), ""); err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+	}
+	return getSession(t, store, id)
+}
+
+func assertRebuilt(t *testing.T, session *DraftSession) {
+	t.Helper()
+	rebuilt, err := session.Rebuild()
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+
+	if !reflect.DeepEqual(rebuilt.Radiant, session.Radiant) || !reflect.DeepEqual(rebuilt.Dire, session.Dire) {
+		t.Errorf("teams: rebuilt %+v / %+v, want %+v / %+v",
+			rebuilt.Radiant, rebuilt.Dire, session.Radiant, session.Dire)
+	}
+	if rebuilt.Step != session.Step || rebuilt.Side != session.Side ||
+		rebuilt.Stage != session.Stage || rebuilt.Completed != session.Completed {
+		t.Errorf("position: rebuilt step %d %s %s (completed %v), want step %d %s %s (completed %v)",
+			rebuilt.Step, rebuilt.Side, rebuilt.Stage, rebuilt.Completed,
+			session.Step, session.Side, session.Stage, session.Completed)
+	}
+	if rebuilt.ReserveBank != session.ReserveBank ||
+		rebuilt.ReserveRadiantMs != session.ReserveRadiantMs || rebuilt.ReserveDireMs != session.ReserveDireMs {
+		t.Errorf("reserve: rebuilt %+v (%d/%d ms), want %+v (%d/%d ms)",
+			rebuilt.ReserveBank, rebuilt.ReserveRadiantMs, rebuilt.ReserveDireMs,
+			session.ReserveBank, session.ReserveRadiantMs, session.ReserveDireMs)
+	}
+	if !reflect.DeepEqual(rebuilt.Events, session.Events) {
+		t.Errorf("events differ:\n got %+v\nwant %+v", rebuilt.Events, session.Events)
+	}
+}
+
+func TestRebuildReproducesFullDraft(t *testing.T) {
+	store, clock, id := timerSession(t)
+	session := playDraft(t, store, clock, id, 100, 5)
+	if !session.Completed {
+		t.Fatalf("draft not completed after %d steps", session.Step)
+	}
+	if len(session.Actions()) != len(session.Order) || len(session.Events) != len(session.Order)+2 {
+		t.Fatalf("journal of %d events (%d actions) for %d turns",
+			len(session.Events), len(session.Actions()), len(session.Order))
+	}
+	// Резерв тратился не целыми секундами
+	if session.ReserveBank.Radiant%time.Second == 0 || session.ReserveBank.Dire%time.Second == 0 {
+		t.Fatalf("reserve bank %+v has no sub-second remainder", session.ReserveBank)
+	}
+	assertRebuilt(t, session)
+}
+
+func TestRebuildMidDraft(t *testing.T) {
+	store, clock, id := timerSession(t)
+	session := playDraft(t, store, clock, id, 9, 2)
+
+	// Сразу после хода собранная сессия стоит в начале следующего хода
+	assertRebuilt(t, session)
+	rebuilt, _ := session.Rebuild()
+	if rebuilt.TurnDeadline != session.TurnDeadline || rebuilt.TimerMs != session.TimerMs {
+		t.Fatalf("turn: rebuilt deadline %v (%d ms), want %v (%d ms)",
+			rebuilt.TurnDeadline, rebuilt.TimerMs, session.TurnDeadline, session.TimerMs)
+	}
+}
+
+func TestRebuildRejectsBrokenJournal(t *testing.T) {
+	store, clock, id := timerSession(t)
+	session := playDraft(t, store, clock, id, 3, -1)
+
+	session.Events[1].Side = opposite(session.Events[1].Side)
+	if _, err := session.Rebuild(); err == nil {
+		t.Fatal("expected an error for an event out of schedule")
+	}
+}
@@ -0,0 +1,173 @@
+package draft
+
+import (
+	"fmt"
+	"sync"
+)
+
+// EventType — тип события сессии, рассылаемого подписчикам.
+type EventType string
+
+const (
+	EventAction         EventType = "action"
+	EventTimer          EventType = "timer"
+	EventReserveStarted EventType = "reserve_started"
+	EventAutoAction     EventType = "auto_action"
+	EventBotThinking    EventType = "bot_thinking"
+	EventCompleted      EventType = "completed"
+	EventHover          EventType = "hover"
+	EventSuggestion     EventType = "suggestion"
+	EventUndo           EventType = "undo"
+	EventPaused         EventType = "paused"
+	EventResumed        EventType = "resumed"
+)
+
+// ActionSource — кто сделал ход.
+type ActionSource string
+
+const (
+	SourceHuman ActionSource = "human"
+	SourceBot   ActionSource = "bot"
+	SourceAuto  ActionSource = "auto"
+)
+
+// BotThinking — бот начал обдумывать ход.
+type BotThinking struct {
+	Side     Side   `json:"side"`
+	Step     int    `json:"step"`
+	DelayMs  int64  `json:"delayMs"`
+	BotSpeed string `json:"botSpeed"`
+}
+
+// HoverInfo — предварительный выбор героя стороной, которая сейчас ходит.
+type HoverInfo struct {
+	Side   Side `json:"side"`
+	Step   int  `json:"step"`
+	HeroID int  `json:"heroId"`
+}
+
+// SessionState — компактный снимок сессии для потоковых обновлений.
+type SessionState struct {
+	Step           int   `json:"step"`
+	Stage          Phase `json:"stage"`
+	Side           Side  `json:"side"`
+	CurrentTimer   int   `json:"currentTimer"`
+	ReserveRadiant int   `json:"reserveRadiant"`
+	ReserveDire    int   `json:"reserveDire"`
+	// Оставшееся время с точностью до миллисекунды
+	TimerMs          int64 `json:"timerMs"`
+	ReserveRadiantMs int64 `json:"reserveRadiantMs"`
+	ReserveDireMs    int64 `json:"reserveDireMs"`
+	Completed        bool  `json:"completed"`
+	Paused           bool  `json:"paused"`
+	Radiant          Team  `json:"radiant"`
+	Dire             Team  `json:"dire"`
+	Pool             []int `json:"pool,omitempty"`
+}
+
+// Event — событие сессии. State — состояние сразу после события.
+type Event struct {
+	Type      EventType    `json:"type"`
+	SessionID string       `json:"sessionId"`
+	State     SessionState `json:"state"`
+	Action    *DraftEvent  `json:"action,omitempty"`
+	Bot       *BotThinking `json:"bot,omitempty"`
+	Hover     *HoverInfo   `json:"hover,omitempty"`
+	// Подсказка тиммейта; доставляется только участникам её стороны
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
+
+	// Токены получателей; nil — событие для всех подписчиков сессии
+	audience map[string]struct{}
+}
+
+// State — снимок сессии для событий.
+func (s *DraftSession) State() SessionState {
+	clone := s.Clone()
+	return SessionState{
+		Step:             clone.Step,
+		Stage:            clone.Stage,
+		Side:             clone.Side,
+		CurrentTimer:     clone.CurrentTimer,
+		ReserveRadiant:   clone.ReserveRadiant,
+		ReserveDire:      clone.ReserveDire,
+		TimerMs:          clone.TimerMs,
+		ReserveRadiantMs: clone.ReserveRadiantMs,
+		ReserveDireMs:    clone.ReserveDireMs,
+		Completed:        clone.Completed,
+		Paused:           clone.Pause != nil,
+		Radiant:          clone.Radiant,
+		Dire:             clone.Dire,
+		Pool:             clone.Pool,
+	}
+}
+
+// subscriberBuffer — сколько событий может накопить медленный подписчик,
+// прежде чем hub его отключит.
+const subscriberBuffer = 64
+
+// hub — pub/sub событий по сессиям. Каждый подписчик хранит токен участника
+// лобби, с которым подключился (пустой — аноним).
+type hub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan Event]string
+}
+
+func newHub() *hub {
+	return &hub{subs: make(map[string]map[chan Event]string)}
+}
+
+func (h *hub) subscribe(sessionID, token string) (chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[sessionID] == nil {
+		h.subs[sessionID] = make(map[chan Event]string)
+	}
+	h.subs[sessionID][ch] = token
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			// Канал отключённого в publish подписчика уже закрыт
+			if _, ok := h.subs[sessionID][ch]; ok {
+				h.drop(sessionID, ch)
+			}
+		})
+	}
+}
+
+// drop удаляет подписчика и закрывает его канал. Вызывается под h.mu.
+func (h *hub) drop(sessionID string, ch chan Event) {
+	delete(h.subs[sessionID], ch)
+	if len(h.subs[sessionID]) == 0 {
+		delete(h.subs, sessionID)
+	}
+	close(ch)
+}
+
+// publish рассылает событие подписчикам сессии, не блокируясь на медленных.
+// Событие с audience получают только подписчики с токеном из него.
+//
+// Подписчик, чей буфер переполнен, отключается: его канал закрывается, и
+// клиент переподключается за свежим состоянием, а не пропускает ходы молча.
+func (h *hub) publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch, token := range h.subs[e.SessionID] {
+		if e.audience != nil {
+			if _, ok := e.audience[token]; !ok || token == "" {
+				continue
+			}
+		}
+		select {
+		case ch <- e:
+		default:
+			fmt.Printf("[HUB] Dropping slow subscriber of session %s\n", e.SessionID)
+			h.drop(e.SessionID, ch)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package draft
+
+import "testing"
+
+func TestHubDeliversToSessionSubscribers(t *testing.T) {
+	h := newHub()
+	a, unsubscribeA := h.subscribe("s1", "")
+	defer unsubscribeA()
+	other, unsubscribeOther := h.subscribe("s2", "")
+	defer unsubscribeOther()
+
+	h.publish(Event{Type: EventAction, SessionID: "s1"})
+	if e := <-a; e.Type != EventAction {
+		t.Fatalf("got %s, want action", e.Type)
+	}
+	if len(other) != 0 {
+		t.Fatal("event of s1 reached a subscriber of s2")
+	}
+}
+
+func TestHubAudience(t *testing.T) {
+	h := newHub()
+	own, unsubscribeOwn := h.subscribe("s1", "radiant-token")
+	defer unsubscribeOwn()
+	enemy, unsubscribeEnemy := h.subscribe("s1", "dire-token")
+	defer unsubscribeEnemy()
+	anonymous, unsubscribeAnonymous := h.subscribe("s1", "")
+	defer unsubscribeAnonymous()
+
+	h.publish(Event{
+		Type:      EventSuggestion,
+		SessionID: "s1",
+		audience:  map[string]struct{}{"radiant-token": {}, "": {}},
+	})
+	if len(own) != 1 || len(enemy) != 0 || len(anonymous) != 0 {
+		t.Fatalf("delivered to own %d, enemy %d, anonymous %d", len(own), len(enemy), len(anonymous))
+	}
+}
+
+func TestHubDisconnectsSlowSubscriber(t *testing.T) {
+	h := newHub()
+	slow, unsubscribeSlow := h.subscribe("s1", "")
+	fast, unsubscribeFast := h.subscribe("s1", "")
+	defer unsubscribeFast()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		h.publish(Event{Type: EventTimer, SessionID: "s1"})
+		<-fast
+	}
+
+	// Буфер дочитывается, после чего канал закрыт
+	for i := 0; i < subscriberBuffer; i++ {
+		if _, ok := <-slow; !ok {
+			t.Fatalf("channel closed after %d of %d buffered events", i, subscriberBuffer)
+		}
+	}
+	if _, ok := <-slow; ok {
+		t.Fatal("slow subscriber is still subscribed")
+	}
+
+	// Отписка уже отключённого подписчика не закрывает канал повторно
+	unsubscribeSlow()
+	unsubscribeSlow()
+	h.publish(Event{Type: EventTimer, SessionID: "s1"})
+	if _, ok := <-fast; !ok {
+		t.Fatal("fast subscriber was dropped")
+	}
+}
+
+func TestHubUnsubscribeTwice(t *testing.T) {
+	h := newHub()
+	ch, unsubscribe := h.subscribe("s1", "")
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-ch; ok {
+		t.Fatal("channel is open after unsubscribe")
+	}
+	if len(h.subs) != 0 {
+		t.Fatalf("subscribers left: %v", h.subs)
+	}
+	h.publish(Event{Type: EventTimer, SessionID: "s1"})
+}
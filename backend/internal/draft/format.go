@@ -0,0 +1,136 @@
+package draft
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+)
+
+// SideRole — чей ход в терминах формата: команды с первым или вторым пиком.
+type SideRole string
+
+const (
+	RoleFirst  SideRole = "first"
+	RoleSecond SideRole = "second"
+)
+
+// DefaultFormat — формат, если клиент не указал format.
+const DefaultFormat = "cm_7_34"
+
+// ErrUnknownFormat возвращается, если запрошен несуществующий формат.
+var ErrUnknownFormat = errors.New("unknown draft format")
+
+// FormatTurn — один ход формата.
+type FormatTurn struct {
+	Phase Phase    `json:"phase"`
+	Side  SideRole `json:"side"`
+	Timer int      `json:"timer"`
+}
+
+// Format — именованный порядок ходов драфта с таймерами и резервом.
+type Format struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	ReserveTime int    `json:"reserveTime"`
+	// PoolSize > 0 — драфт идёт из случайного пула героев (Captains Draft)
+	PoolSize int          `json:"poolSize,omitempty"`
+	Turns    []FormatTurn `json:"turns"`
+}
+
+// Schedule разворачивает формат в конкретный порядок ходов для стороны с первым пиком.
+func (f Format) Schedule(firstPick Side) []Turn {
+	fp, sp := firstPick, opposite(firstPick)
+
+	order := make([]Turn, 0, len(f.Turns))
+	for _, t := range f.Turns {
+		side := fp
+		if t.Side == RoleSecond {
+			side = sp
+		}
+		order = append(order, Turn{Phase: t.Phase, Side: side, Timer: t.Timer})
+	}
+	return order
+}
+
+func (f Format) validate() error {
+	if f.Name == "" {
+		return errors.New("format without name")
+	}
+	if len(f.Turns) == 0 {
+		return fmt.Errorf("format %s has no turns", f.Name)
+	}
+	if f.PoolSize < 0 || (f.PoolSize > 0 && f.PoolSize < len(f.Turns)) {
+		return fmt.Errorf("format %s: pool of %d heroes is too small for %d turns", f.Name, f.PoolSize, len(f.Turns))
+	}
+	for i, t := range f.Turns {
+		if t.Phase != PhaseBan && t.Phase != PhasePick {
+			return fmt.Errorf("format %s turn %d: unknown phase %q", f.Name, i, t.Phase)
+		}
+		if t.Side != RoleFirst && t.Side != RoleSecond {
+			return fmt.Errorf("format %s turn %d: unknown side %q", f.Name, i, t.Side)
+		}
+		if t.Timer <= 0 {
+			return fmt.Errorf("format %s turn %d: timer must be positive", f.Name, i)
+		}
+	}
+	return nil
+}
+
+//go:embed formats/*.json
+var formatFiles embed.FS
+
+// formats — встроенные форматы по имени, загружаются при старте.
+var formats = mustLoadFormats()
+
+func mustLoadFormats() map[string]Format {
+	entries, err := formatFiles.ReadDir("formats")
+	if err != nil {
+		panic(err)
+	}
+
+	result := make(map[string]Format, len(entries))
+	for _, entry := range entries {
+		data, err := formatFiles.ReadFile(path.Join("formats", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		var f Format
+		if err := json.Unmarshal(data, &f); err != nil {
+			panic(fmt.Sprintf("draft: invalid format file %s: %v", entry.Name(), err))
+		}
+		if f.ReserveTime == 0 {
+			f.ReserveTime = ReserveTimeSeconds
+		}
+		if err := f.validate(); err != nil {
+			panic(fmt.Sprintf("draft: invalid format file %s: %v", entry.Name(), err))
+		}
+		result[f.Name] = f
+	}
+	return result
+}
+
+// LookupFormat возвращает формат по имени; пустое имя — формат по умолчанию.
+func LookupFormat(name string) (Format, error) {
+	if name == "" {
+		name = DefaultFormat
+	}
+	f, ok := formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("%w %q", ErrUnknownFormat, name)
+	}
+	return f, nil
+}
+
+// Formats возвращает все встроенные форматы, отсортированные по имени.
+func Formats() []Format {
+	result := make([]Format, 0, len(formats))
+	for _, f := range formats {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
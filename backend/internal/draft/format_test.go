@@ -0,0 +1,81 @@
+package draft
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEmbeddedFormatsParse(t *testing.T) {
+	names := make([]string, 0)
+	for _, f := range Formats() {
+		names = append(names, f.Name)
+		if err := f.validate(); err != nil {
+			t.Errorf("format %s: %v", f.Name, err)
+		}
+		if f.Title == "" || f.ReserveTime <= 0 {
+			t.Errorf("format %s: title %q, reserve %d", f.Name, f.Title, f.ReserveTime)
+		}
+	}
+	if want := []string{"cd", "cm_7_00", "cm_7_34"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("formats %v, want %v", names, want)
+	}
+
+	if f, err := LookupFormat(""); err != nil || f.Name != DefaultFormat {
+		t.Fatalf("default format: %+v, %v", f.Name, err)
+	}
+	if _, err := LookupFormat("cm_6_88"); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("unknown format: %v", err)
+	}
+}
+
+// legacySchedule — порядок Captains Mode 7.34, который был зашит в код до
+// появления форматов.
+func legacySchedule(firstPick Side) []Turn {
+	fp, sp := firstPick, opposite(firstPick)
+	return []Turn{
+		{PhaseBan, fp, 15}, {PhaseBan, sp, 15}, {PhaseBan, fp, 15}, {PhaseBan, sp, 15},
+		{PhaseBan, sp, 15}, {PhaseBan, fp, 15}, {PhaseBan, sp, 15},
+		{PhasePick, fp, 30}, {PhasePick, sp, 30},
+		{PhaseBan, fp, 25}, {PhaseBan, fp, 25}, {PhaseBan, sp, 25},
+		{PhasePick, sp, 35}, {PhasePick, sp, 35}, {PhasePick, fp, 35},
+		{PhasePick, fp, 35}, {PhasePick, sp, 35}, {PhasePick, fp, 35},
+		{PhaseBan, sp, 30}, {PhaseBan, sp, 30}, {PhaseBan, fp, 30}, {PhaseBan, fp, 30},
+		{PhasePick, fp, 40}, {PhasePick, sp, 40},
+	}
+}
+
+func TestCM734MatchesLegacySchedule(t *testing.T) {
+	format, err := LookupFormat("cm_7_34")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format.ReserveTime != ReserveTimeSeconds || format.PoolSize != 0 {
+		t.Fatalf("reserve %d, pool %d", format.ReserveTime, format.PoolSize)
+	}
+	for _, side := range []Side{SideRadiant, SideDire} {
+		if got, want := format.Schedule(side), legacySchedule(side); !reflect.DeepEqual(got, want) {
+			t.Errorf("first pick %s:\n got %v\nwant %v", side, got, want)
+		}
+	}
+}
+
+func TestFormatValidate(t *testing.T) {
+	turn := FormatTurn{Phase: PhasePick, Side: RoleFirst, Timer: 30}
+	tests := []struct {
+		name   string
+		format Format
+	}{
+		{"no name", Format{Turns: []FormatTurn{turn}}},
+		{"no turns", Format{Name: "x"}},
+		{"pool too small", Format{Name: "x", PoolSize: 1, Turns: []FormatTurn{turn, turn}}},
+		{"unknown phase", Format{Name: "x", Turns: []FormatTurn{{Phase: "swap", Side: RoleFirst, Timer: 1}}}},
+		{"unknown side", Format{Name: "x", Turns: []FormatTurn{{Phase: PhaseBan, Side: "third", Timer: 1}}}},
+		{"zero timer", Format{Name: "x", Turns: []FormatTurn{{Phase: PhaseBan, Side: RoleSecond}}}},
+	}
+	for _, tt := range tests {
+		if err := tt.format.validate(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
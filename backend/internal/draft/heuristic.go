@@ -0,0 +1,121 @@
+package draft
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+// HeuristicBot — бот, собирающий сбалансированный состав по ролям героев.
+// Пикает героев, закрывающих недостающие роли, а банит тех, кто лучше
+// всего дополнит состав соперника.
+type HeuristicBot struct{}
+
+// lineupRoles — роли, которые бот старается закрыть в составе, и их вес.
+var lineupRoles = map[string]float64{
+	"Carry":     3,
+	"Support":   3,
+	"Initiator": 2,
+	"Disabler":  2,
+	"Nuker":     1.5,
+	"Durable":   1.5,
+}
+
+// maxRoleStack — сколько героев одной роли ещё имеет смысл брать.
+// Пятый саппорт или третий керри только вредят составу.
+var maxRoleStack = map[string]int{
+	"Carry":   2,
+	"Support": 2,
+}
+
+// ChooseHero выбирает героя для текущего хода сессии.
+func (b HeuristicBot) ChooseHero(session *DraftSession) int {
+	catalog := session.catalogHeroes()
+	if len(catalog) == 0 {
+		return 0
+	}
+
+	byID := make(map[int]heroes.Hero, len(catalog))
+	for _, h := range catalog {
+		byID[h.ID] = h
+	}
+
+	// Пик — усиливаем свой состав, бан — отнимаем лучший вариант у соперника.
+	lineup := session.activeTeam().Picks
+	if session.Stage == PhaseBan {
+		lineup = session.opponentTeam().Picks
+	}
+	current := make([]heroes.Hero, 0, len(lineup)+1)
+	for _, id := range lineup {
+		if h, ok := byID[id]; ok {
+			current = append(current, h)
+		}
+	}
+	base := lineupScore(current)
+
+	best, bestScore := 0, 0.0
+	for _, h := range catalog {
+		if !session.IsHeroAvailable(h.ID) {
+			continue
+		}
+		// Банить героя, которого соперник и так не может пикнуть, бессмысленно.
+		if session.Stage == PhaseBan && session.IsLocked(opposite(session.Side), h.ID) {
+			continue
+		}
+		// Небольшой шум, чтобы бот не играл одинаково при равных оценках.
+		score := lineupScore(append(current, h)) - base + rand.Float64()*0.05
+		if best == 0 || score > bestScore {
+			best, bestScore = h.ID, score
+		}
+	}
+
+	if best != 0 {
+		fmt.Printf("[BOT] %s heuristic-%s hero %d (gain %.2f)\n",
+			session.Side, session.Stage, best, bestScore)
+	}
+	return best
+}
+
+// lineupScore оценивает состав: покрытие ролей с убывающей отдачей,
+// штраф за перебор керри/саппортов, разнообразие атрибутов и типов атаки.
+func lineupScore(lineup []heroes.Hero) float64 {
+	roleCount := make(map[string]int)
+	attrs := make(map[string]struct{})
+	melee, ranged := 0, 0
+
+	for _, h := range lineup {
+		for _, role := range h.Roles {
+			roleCount[role]++
+		}
+		attrs[h.PrimaryAttr] = struct{}{}
+		switch h.AttackType {
+		case "Melee":
+			melee++
+		case "Ranged":
+			ranged++
+		}
+	}
+
+	score := 0.0
+	for role, weight := range lineupRoles {
+		count := roleCount[role]
+		// 1-й герой роли даёт полный вес, 2-й — половину, 3-й — четверть...
+		gain := 0.0
+		for i, w := 0, weight; i < count; i, w = i+1, w/2 {
+			gain += w
+		}
+		if limit, ok := maxRoleStack[role]; ok && count > limit {
+			gain -= float64(count-limit) * weight
+		}
+		score += gain
+	}
+
+	score += 0.5 * float64(len(attrs))
+
+	// Чисто мили или чисто рендж составы после трёх пиков — минус.
+	if len(lineup) >= 3 && (melee == 0 || ranged == 0) {
+		score -= 1
+	}
+	return score
+}
@@ -0,0 +1,80 @@
+package draft
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+// staticHeroes — провайдер каталога с заданным списком героев.
+type staticHeroes []heroes.Hero
+
+func (p staticHeroes) Name() string                   { return "static" }
+func (p staticHeroes) Heroes() ([]heroes.Hero, error) { return p, nil }
+
+// testCatalog загружает каталог из списка героев.
+func testCatalog(t *testing.T, list ...heroes.Hero) *heroes.Catalog {
+	t.Helper()
+	catalog := heroes.NewCatalog(staticHeroes(list))
+	if err := catalog.Load(); err != nil {
+		t.Fatalf("load catalog: %v", err)
+	}
+	return catalog
+}
+
+func hero(id int, attr, attack string, roles ...string) heroes.Hero {
+	return heroes.Hero{ID: id, Name: "hero", PrimaryAttr: attr, AttackType: attack, Roles: roles}
+}
+
+func TestLineupScoreDiminishingReturns(t *testing.T) {
+	carry := hero(1, "agi", "Melee", "Carry")
+	tests := []struct {
+		name   string
+		lineup []heroes.Hero
+		want   float64
+	}{
+		{"empty", nil, 0},
+		// Керри: 3, затем половина и четверть веса; атрибут даёт 0.5.
+		{"one carry", []heroes.Hero{carry}, 3 + 0.5},
+		{"two carries", []heroes.Hero{carry, carry}, 3 + 1.5 + 0.5},
+		// Третий керри сверх maxRoleStack стоит полного веса роли и
+		// однородный по типу атаки состав из трёх героев штрафуется.
+		{"three carries", []heroes.Hero{carry, carry, carry}, 3 + 1.5 + 0.75 - 3 + 0.5 - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineupScore(tt.lineup); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("lineupScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeuristicBotFillsMissingRole(t *testing.T) {
+	catalog := testCatalog(t,
+		hero(1, "agi", "Melee", "Carry"),
+		hero(2, "agi", "Ranged", "Carry"),
+		hero(3, "agi", "Melee", "Carry"),
+		hero(4, "int", "Ranged", "Support"),
+		hero(5, "str", "Melee", "Durable"),
+	)
+	session := newDraftSession("heuristic", "Radiant", "Dire", SideRadiant, formats[DefaultFormat], time.Now())
+	session.catalog = catalog
+	session.Stage, session.Side = PhasePick, SideRadiant
+	session.Radiant.Picks = []int{1, 2}
+	session.taken[1], session.taken[2] = struct{}{}, struct{}{}
+
+	// Два керри уже есть: третий только вредит, саппорт закрывает тяжёлую роль.
+	if got := (HeuristicBot{}).ChooseHero(session); got != 4 {
+		t.Fatalf("pick = %d, want the support 4", got)
+	}
+
+	// В бан уходит герой, который лучше всего дополнит состав соперника.
+	session.Stage = PhaseBan
+	session.Radiant.Picks, session.Dire.Picks = nil, []int{1, 2}
+	if got := (HeuristicBot{}).ChooseHero(session); got != 4 {
+		t.Fatalf("ban = %d, want the support 4", got)
+	}
+}
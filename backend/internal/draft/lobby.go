@@ -0,0 +1,180 @@
+package draft
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// LobbyRole — роль участника лобби.
+type LobbyRole string
+
+const (
+	// LobbyCaptain делает пики и баны за свою сторону.
+	LobbyCaptain LobbyRole = "captain"
+	// LobbyTeammate может предлагать героев капитану своей стороны.
+	LobbyTeammate LobbyRole = "teammate"
+	// LobbySpectator только наблюдает.
+	LobbySpectator LobbyRole = "spectator"
+	// LobbyCoach не привязан к стороне: откатывает ходы обеих сторон
+	// в тренировке и ставит паузу из своего бюджета, а не бюджета команд.
+	LobbyCoach LobbyRole = "coach"
+)
+
+var (
+	// ErrSessionNotFound возвращается для неизвестного id сессии.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrForbidden — у вызывающего нет прав на действие.
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidJoin — ошибка в запросе на вход в лобби.
+	ErrInvalidJoin = errors.New("invalid join request")
+)
+
+// LobbyMember — участник лобби. Токен выдаётся только при входе и в JSON
+// сессии не попадает.
+type LobbyMember struct {
+	Name  string    `json:"name"`
+	Role  LobbyRole `json:"role"`
+	Side  Side      `json:"side,omitempty"`
+	Token string    `json:"-"`
+}
+
+// Suggestion — герой, предложенный участником своей стороне.
+type Suggestion struct {
+	Side   Side   `json:"side"`
+	Name   string `json:"name"`
+	HeroID int    `json:"heroId"`
+}
+
+// captain возвращает капитана стороны, если он есть.
+func (s *DraftSession) captain(side Side) (LobbyMember, bool) {
+	for _, m := range s.Lobby {
+		if m.Role == LobbyCaptain && m.Side == side {
+			return m, true
+		}
+	}
+	return LobbyMember{}, false
+}
+
+// member ищет участника по токену.
+func (s *DraftSession) member(token string) (LobbyMember, bool) {
+	if token == "" {
+		return LobbyMember{}, false
+	}
+	for _, m := range s.Lobby {
+		if m.Token == token {
+			return m, true
+		}
+	}
+	return LobbyMember{}, false
+}
+
+// authorizeTurn проверяет, может ли владелец токена ходить за текущую сторону.
+// Сторона бота недоступна людям. Пока лобби пусто, сторона открыта (одиночная
+// тренировка); как только в лобби кто-то вошёл, ходить может только капитан
+// текущей стороны.
+func (s *DraftSession) authorizeTurn(token string) error {
+	if s.Side == s.BotSide {
+		return fmt.Errorf("%w: %s is controlled by the bot", ErrForbidden, s.Side)
+	}
+	if len(s.Lobby) == 0 {
+		return nil
+	}
+	captain, ok := s.captain(s.Side)
+	if !ok {
+		return fmt.Errorf("%w: %s has no captain in the lobby", ErrForbidden, s.Side)
+	}
+	if token != captain.Token {
+		return fmt.Errorf("%w: only the %s captain can act now", ErrForbidden, s.Side)
+	}
+	return nil
+}
+
+// sideTokens — токены капитана и тиммейтов стороны: только им доставляются
+// подсказки этой стороны.
+func (s *DraftSession) sideTokens(side Side) map[string]struct{} {
+	tokens := make(map[string]struct{})
+	for _, m := range s.Lobby {
+		if m.Side == side && m.Role != LobbySpectator {
+			tokens[m.Token] = struct{}{}
+		}
+	}
+	return tokens
+}
+
+// Join добавляет участника в лобби сессии и возвращает его с токеном.
+// key — ключ тренера сессии, нужен только для роли coach.
+func (s *Store) Join(id, name string, role LobbyRole, side Side, key string) (LobbyMember, error) {
+	if name == "" {
+		return LobbyMember{}, fmt.Errorf("%w: name is required", ErrInvalidJoin)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return LobbyMember{}, ErrSessionNotFound
+	}
+
+	switch role {
+	case LobbyCaptain, LobbyTeammate:
+		if side != SideRadiant && side != SideDire {
+			return LobbyMember{}, fmt.Errorf("%w: %s must choose radiant or dire", ErrInvalidJoin, role)
+		}
+		if side == session.BotSide {
+			return LobbyMember{}, fmt.Errorf("%w: %s is controlled by the bot", ErrInvalidJoin, side)
+		}
+		if _, taken := session.captain(side); role == LobbyCaptain && taken {
+			return LobbyMember{}, fmt.Errorf("%w: %s already has a captain", ErrInvalidJoin, side)
+		}
+	case LobbyCoach:
+		// Тренер откатывает ходы обеих сторон и ставит паузы, поэтому
+		// назваться тренером может только владелец ключа создателя сессии.
+		if session.CoachKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(session.CoachKey)) != 1 {
+			return LobbyMember{}, fmt.Errorf("%w: a valid coach key is required", ErrForbidden)
+		}
+		side = ""
+	case LobbySpectator:
+		side = ""
+	default:
+		return LobbyMember{}, fmt.Errorf("%w: unknown role %q", ErrInvalidJoin, role)
+	}
+
+	member := LobbyMember{Name: name, Role: role, Side: side, Token: generateID() + generateID()}
+	session.Lobby = append(session.Lobby, member)
+	s.persist(session)
+	fmt.Printf("[LOBBY] %s joined %s as %s %s\n", name, id, side, role)
+	return member, nil
+}
+
+// Suggest рассылает героя, предложенного капитаном или тиммейтом, участникам
+// его стороны. Соперник и зрители подсказку не получают.
+func (s *Store) Suggest(id, token string, heroID int) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	member, ok := session.member(token)
+	if !ok || (member.Role != LobbyCaptain && member.Role != LobbyTeammate) {
+		return fmt.Errorf("%w: only captains and teammates can suggest heroes", ErrForbidden)
+	}
+	if session.Completed {
+		return errors.New("draft is already completed")
+	}
+	if heroID <= 0 || !session.InPool(heroID) || session.IsHeroUsed(heroID) {
+		return fmt.Errorf("hero %d is not available", heroID)
+	}
+
+	s.events.publish(Event{
+		Type:       EventSuggestion,
+		SessionID:  id,
+		State:      s.view(session).State(),
+		Suggestion: &Suggestion{Side: member.Side, Name: member.Name, HeroID: heroID},
+		audience:   session.sideTokens(member.Side),
+	})
+	return nil
+}
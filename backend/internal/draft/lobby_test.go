@@ -0,0 +1,172 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newHumanSession создаёт сессию двух команд людей на остановленных часах.
+func newHumanSession(t *testing.T) (*Store, *DraftSession) {
+	t.Helper()
+	store := NewStoreWithConfig(StoreConfig{Clock: NewFakeClock(time.Unix(0, 0))})
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return store, session
+}
+
+func join(t *testing.T, store *Store, id, name string, role LobbyRole, side Side) LobbyMember {
+	t.Helper()
+	member, err := store.Join(id, name, role, side, "")
+	if err != nil {
+		t.Fatalf("join %s: %v", name, err)
+	}
+	return member
+}
+
+// joinCoach входит тренером с ключом, выданным создателю сессии.
+func joinCoach(t *testing.T, store *Store, session *DraftSession, name string) LobbyMember {
+	t.Helper()
+	member, err := store.Join(session.ID, name, LobbyCoach, "", session.CoachKey)
+	if err != nil {
+		t.Fatalf("join coach %s: %v", name, err)
+	}
+	return member
+}
+
+func act(store *Store, session *DraftSession, token string) error {
+	_, err := store.ApplyAction(session.ID, token, session.Stage, firstAvailableHero(session))
+	return err
+}
+
+func TestAuthorizeTurnOpenWithoutLobby(t *testing.T) {
+	store, session := newHumanSession(t)
+	if err := act(store, session, ""); err != nil {
+		t.Fatalf("session without a lobby should be open: %v", err)
+	}
+}
+
+func TestAuthorizeTurnRequiresCaptainOnceLobbyExists(t *testing.T) {
+	store, session := newHumanSession(t)
+	join(t, store, session.ID, "viewer", LobbySpectator, "")
+
+	// Даже без капитана стороны анонимный ход больше не проходит.
+	if err := act(store, session, ""); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("anonymous action in a lobby: err = %v, want ErrForbidden", err)
+	}
+
+	dire := join(t, store, session.ID, "Collapse", LobbyCaptain, SideDire)
+	if err := act(store, session, dire.Token); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("dire captain on radiant turn: err = %v, want ErrForbidden", err)
+	}
+
+	radiant := join(t, store, session.ID, "Miracle", LobbyCaptain, SideRadiant)
+	if err := act(store, session, radiant.Token); err != nil {
+		t.Fatalf("radiant captain action: %v", err)
+	}
+}
+
+func TestSuggestionReachesOnlyOwnSide(t *testing.T) {
+	store, session := newHumanSession(t)
+	captain := join(t, store, session.ID, "Miracle", LobbyCaptain, SideRadiant)
+	teammate := join(t, store, session.ID, "Boxi", LobbyTeammate, SideRadiant)
+	enemy := join(t, store, session.ID, "Collapse", LobbyCaptain, SideDire)
+	spectator := join(t, store, session.ID, "viewer", LobbySpectator, "")
+
+	subscribe := func(token string) <-chan Event {
+		_, events, unsubscribe, err := store.Subscribe(session.ID, token)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		t.Cleanup(unsubscribe)
+		return events
+	}
+	subscribers := map[string]<-chan Event{
+		"captain":   subscribe(captain.Token),
+		"teammate":  subscribe(teammate.Token),
+		"enemy":     subscribe(enemy.Token),
+		"spectator": subscribe(spectator.Token),
+		"anonymous": subscribe(""),
+	}
+
+	heroID := firstAvailableHero(session)
+	if err := store.Suggest(session.ID, teammate.Token, heroID); err != nil {
+		t.Fatalf("suggest: %v", err)
+	}
+
+	want := map[string]bool{"captain": true, "teammate": true}
+	for name, events := range subscribers {
+		// Кроме подсказки в канале могут быть тики таймера.
+		var got *Suggestion
+	drain:
+		for {
+			select {
+			case event := <-events:
+				if event.Suggestion != nil {
+					got = event.Suggestion
+				}
+			default:
+				break drain
+			}
+		}
+		if want[name] != (got != nil) {
+			t.Errorf("%s received suggestion %+v, want delivered=%v", name, got, want[name])
+		}
+		if got != nil && got.HeroID != heroID {
+			t.Errorf("%s received hero %d, want %d", name, got.HeroID, heroID)
+		}
+	}
+}
+
+func TestJoinAsCoachRequiresKey(t *testing.T) {
+	store, session := newHumanSession(t)
+	_, other := newHumanSession(t)
+	if session.CoachKey == "" || session.CoachKey == other.CoachKey {
+		t.Fatalf("sessions got coach keys %q and %q", session.CoachKey, other.CoachKey)
+	}
+
+	for name, key := range map[string]string{
+		"no key":        "",
+		"wrong key":     "guess",
+		"other session": other.CoachKey,
+	} {
+		if _, err := store.Join(session.ID, "Puppey", LobbyCoach, "", key); !errors.Is(err, ErrForbidden) {
+			t.Errorf("%s: err = %v, want ErrForbidden", name, err)
+		}
+	}
+	got := getSession(t, store, session.ID)
+	if len(got.Lobby) != 0 {
+		t.Fatalf("rejected coach entered the lobby: %+v", got.Lobby)
+	}
+
+	coach := joinCoach(t, store, session, "Puppey")
+	if coach.Role != LobbyCoach || coach.Side != "" {
+		t.Fatalf("coach %+v", coach)
+	}
+
+	// Ключ переживает сохранение и подъём из хранилища
+	if restored := newRecord(got, time.Unix(0, 0)).restore(); restored.CoachKey != session.CoachKey {
+		t.Fatalf("restored coach key %q, want %q", restored.CoachKey, session.CoachKey)
+	}
+}
+
+func TestForkGetsOwnCoachKey(t *testing.T) {
+	store, session := newHumanSession(t)
+	forked, err := store.Fork(session.ID, 0)
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	if forked.CoachKey == "" || forked.CoachKey == session.CoachKey {
+		t.Fatalf("fork coach key %q, source %q", forked.CoachKey, session.CoachKey)
+	}
+	if _, err := store.Join(forked.ID, "Puppey", LobbyCoach, "", session.CoachKey); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("source key opened the fork: %v", err)
+	}
+}
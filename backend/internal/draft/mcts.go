@@ -0,0 +1,358 @@
+package draft
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+// Evaluator оценивает законченный драфт 5x5 — возвращает вероятность победы
+// Radiant в диапазоне [0, 1].
+type Evaluator func(radiant, dire []int) float64
+
+// PlanReporter — бот, который может рассказать, какое продолжение драфта
+// он ожидает после своего хода.
+type PlanReporter interface {
+	PrincipalVariation() []int
+}
+
+const (
+	// mctsExploration — константа UCT (≈√2 для наград в [0, 1]).
+	mctsExploration = 1.4
+	// mctsPoolExtra — сколько героев сверх оставшихся ходов берём в пул поиска.
+	mctsPoolExtra = 16
+	// mctsMaxPlayouts ограничивает поиск, если часы сессии стоят (FakeClock
+	// в тестах): тогда бюджет времени не кончается никогда.
+	mctsMaxPlayouts = 20000
+)
+
+// MCTSBot — бот, который проигрывает оставшуюся часть Order методом
+// Monte Carlo Tree Search и выбирает ход с наибольшим числом посещений.
+type MCTSBot struct {
+	Evaluate Evaluator
+
+	mu sync.Mutex
+	pv []int
+}
+
+// NewMCTSBot создаёт MCTS-бота с функцией оценки итогового драфта.
+func NewMCTSBot(evaluate Evaluator) *MCTSBot {
+	return &MCTSBot{Evaluate: evaluate}
+}
+
+// searchBudget — сколько времени бот ищет ход. Бюджет меньше минимальной
+// задержки botThinkDelay, поэтому поиск укладывается в «задумчивость» бота.
+func searchBudget(speed string) time.Duration {
+	switch speed {
+	case "fast":
+		return 800 * time.Millisecond
+	case "slow":
+		return 6 * time.Second
+	default:
+		return 2500 * time.Millisecond
+	}
+}
+
+// PrincipalVariation возвращает ожидаемое продолжение после последнего поиска:
+// первый элемент — ход бота, далее наиболее исследованная линия.
+func (b *MCTSBot) PrincipalVariation() []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]int(nil), b.pv...)
+}
+
+// ChooseHero выбирает героя для текущего хода сессии.
+func (b *MCTSBot) ChooseHero(session *DraftSession) int {
+	if b.Evaluate == nil || session.Completed {
+		return 0
+	}
+
+	root := newSearchState(session)
+	pool := b.candidatePool(root, session.Side)
+	if len(pool) == 0 {
+		return 0
+	}
+
+	tree := &mctsNode{untried: root.available(pool)}
+	// Бюджет отсчитывается по часам сессии, как таймеры и задержка бота.
+	deadline := session.now().Add(searchBudget(session.BotSpeed))
+	iterations := 0
+	for iterations < mctsMaxPlayouts && session.now().Before(deadline) {
+		b.iterate(tree, root.clone(), pool)
+		iterations++
+	}
+
+	pv := tree.principalVariation()
+	b.mu.Lock()
+	b.pv = pv
+	b.mu.Unlock()
+
+	if len(pv) == 0 {
+		return 0
+	}
+	fmt.Printf("[BOT] %s mcts-%s hero %d after %d playouts, pv %v\n",
+		session.Side, session.Stage, pv[0], iterations, pv)
+	return pv[0]
+}
+
+// iterate — одна итерация MCTS: выбор, расширение, розыгрыш и обратное распространение.
+func (b *MCTSBot) iterate(node *mctsNode, state *searchState, pool []int) {
+	// Selection
+	for len(node.untried) == 0 && len(node.children) > 0 {
+		node = node.selectChild()
+		state.apply(node.move)
+	}
+
+	// Expansion
+	if len(node.untried) > 0 && !state.done() {
+		i := rand.Intn(len(node.untried))
+		move := node.untried[i]
+		node.untried = append(node.untried[:i], node.untried[i+1:]...)
+
+		actor := state.turn().Side
+		state.apply(move)
+		child := &mctsNode{move: move, actor: actor, parent: node}
+		if !state.done() {
+			child.untried = state.available(pool)
+		}
+		node.children = append(node.children, child)
+		node = child
+	}
+
+	// Rollout
+	for !state.done() {
+		options := state.available(pool)
+		if len(options) == 0 {
+			options = state.available(nil)
+		}
+		if len(options) == 0 {
+			break
+		}
+		state.apply(options[rand.Intn(len(options))])
+	}
+	radiantWin := b.Evaluate(state.picks[SideRadiant], state.picks[SideDire])
+
+	// Backpropagation: каждый узел хранит выигрыш стороны, сделавшей его ход.
+	for ; node != nil; node = node.parent {
+		node.visits++
+		if node.actor == SideRadiant {
+			node.wins += radiantWin
+		} else {
+			node.wins += 1 - radiantWin
+		}
+	}
+}
+
+// candidatePool ограничивает ветвление: берёт героев, которые сильнее всего
+// меняют оценку, если достанутся любой из сторон.
+func (b *MCTSBot) candidatePool(state *searchState, side Side) []int {
+	// state.all — все свободные герои, включая закрытые fearless для одной из сторон.
+	available := state.all
+	size := state.remaining() + mctsPoolExtra
+	if len(available) <= size {
+		return available
+	}
+
+	own, enemy := state.picks[side], state.picks[opposite(side)]
+	scored := make([]scoredHero, 0, len(available))
+	for _, h := range available {
+		forUs := b.sideValue(side, append(append([]int(nil), own...), h), enemy)
+		forThem := 1 - b.sideValue(side, own, append(append([]int(nil), enemy...), h))
+		scored = append(scored, scoredHero{id: h, score: math.Max(forUs, forThem)})
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+
+	pool := make([]int, 0, size)
+	for _, s := range scored[:size] {
+		pool = append(pool, s.id)
+	}
+	return pool
+}
+
+// sideValue — вероятность победы side при данных составах.
+func (b *MCTSBot) sideValue(side Side, own, enemy []int) float64 {
+	if side == SideRadiant {
+		return b.Evaluate(own, enemy)
+	}
+	return 1 - b.Evaluate(enemy, own)
+}
+
+// mctsNode — узел дерева поиска.
+type mctsNode struct {
+	move    int  // герой, которым сделан ход в этот узел
+	actor   Side // кто сделал move
+	parent  *mctsNode
+	untried []int
+
+	children []*mctsNode
+	visits   int
+	wins     float64
+}
+
+func (n *mctsNode) selectChild() *mctsNode {
+	logN := math.Log(float64(n.visits))
+	var best *mctsNode
+	bestScore := math.Inf(-1)
+	for _, c := range n.children {
+		score := c.wins/float64(c.visits) + mctsExploration*math.Sqrt(logN/float64(c.visits))
+		if score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
+
+func (n *mctsNode) principalVariation() []int {
+	var pv []int
+	for node := n; len(node.children) > 0; {
+		best := node.children[0]
+		for _, c := range node.children[1:] {
+			if c.visits > best.visits {
+				best = c
+			}
+		}
+		pv = append(pv, best.move)
+		node = best
+	}
+	return pv
+}
+
+// searchState — облегчённая копия драфта для симуляций.
+type searchState struct {
+	order  []Turn
+	step   int
+	used   map[int]struct{}
+	picks  map[Side][]int
+	locked map[Side]map[int]struct{}
+	all    []int
+}
+
+func newSearchState(session *DraftSession) *searchState {
+	state := &searchState{
+		order: session.Order,
+		step:  session.Step,
+		used:  make(map[int]struct{}),
+		picks: map[Side][]int{
+			SideRadiant: append([]int(nil), session.Radiant.Picks...),
+			SideDire:    append([]int(nil), session.Dire.Picks...),
+		},
+		locked: make(map[Side]map[int]struct{}),
+	}
+	for side, ids := range session.Locked {
+		state.locked[side] = make(map[int]struct{}, len(ids))
+		for _, h := range ids {
+			state.locked[side][h] = struct{}{}
+		}
+	}
+	for _, h := range session.catalogHeroes() {
+		// Герои вне пула для поиска равносильны уже занятым.
+		if !session.InPool(h.ID) || session.IsHeroUsed(h.ID) {
+			state.used[h.ID] = struct{}{}
+			continue
+		}
+		state.all = append(state.all, h.ID)
+	}
+	return state
+}
+
+func (s *searchState) clone() *searchState {
+	c := &searchState{
+		order: s.order,
+		step:  s.step,
+		used:  make(map[int]struct{}, len(s.used)+s.remaining()),
+		picks: map[Side][]int{
+			SideRadiant: append([]int(nil), s.picks[SideRadiant]...),
+			SideDire:    append([]int(nil), s.picks[SideDire]...),
+		},
+		locked: s.locked,
+		all:    s.all,
+	}
+	for h := range s.used {
+		c.used[h] = struct{}{}
+	}
+	return c
+}
+
+func (s *searchState) done() bool     { return s.step >= len(s.order) }
+func (s *searchState) remaining() int { return len(s.order) - s.step }
+func (s *searchState) turn() Turn     { return s.order[s.step] }
+
+func (s *searchState) apply(heroID int) {
+	turn := s.turn()
+	if turn.Phase == PhasePick {
+		s.picks[turn.Side] = append(s.picks[turn.Side], heroID)
+	}
+	s.used[heroID] = struct{}{}
+	s.step++
+}
+
+// available возвращает героев из pool (nil — из всех героев), которыми
+// можно сделать текущий ход.
+func (s *searchState) available(pool []int) []int {
+	if pool == nil {
+		pool = s.all
+	}
+	var locked map[int]struct{}
+	if !s.done() && s.turn().Phase == PhasePick {
+		locked = s.locked[s.turn().Side]
+	}
+
+	result := make([]int, 0, len(pool))
+	for _, h := range pool {
+		if _, used := s.used[h]; used {
+			continue
+		}
+		if _, banned := locked[h]; banned {
+			continue
+		}
+		result = append(result, h)
+	}
+	return result
+}
+
+// DefaultEvaluator оценивает драфт по покрытию ролей (как HeuristicBot) и,
+// если передана матрица, по суммарному преимуществу и синергии героев.
+// Каталог героев снимается один раз при создании оценщика.
+func DefaultEvaluator(catalog *heroes.Catalog, matchups MatchupSource) Evaluator {
+	byID := make(map[int]heroes.Hero)
+	for _, h := range catalog.All() {
+		byID[h.ID] = h
+	}
+
+	lineup := func(ids []int) []heroes.Hero {
+		result := make([]heroes.Hero, 0, len(ids))
+		for _, id := range ids {
+			if h, ok := byID[id]; ok {
+				result = append(result, h)
+			}
+		}
+		return result
+	}
+
+	return func(radiant, dire []int) float64 {
+		diff := 0.1 * (lineupScore(lineup(radiant)) - lineupScore(lineup(dire)))
+		if matchups != nil {
+			for _, r := range radiant {
+				for _, d := range dire {
+					diff += matchups.Advantage(r, d)
+				}
+			}
+			for i, a := range radiant {
+				for _, b := range radiant[i+1:] {
+					diff += matchups.Synergy(a, b)
+				}
+			}
+			for i, a := range dire {
+				for _, b := range dire[i+1:] {
+					diff -= matchups.Synergy(a, b)
+				}
+			}
+		}
+		return 1 / (1 + math.Exp(-4*diff))
+	}
+}
@@ -0,0 +1,51 @@
+package draft
+
+import (
+	"testing"
+	"time"
+)
+
+// steppingClock — часы, которые сдвигаются на step при каждом Now.
+type steppingClock struct {
+	now   time.Time
+	step  time.Duration
+	calls int
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.calls++
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func (c *steppingClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func TestMCTSBudgetUsesSessionClock(t *testing.T) {
+	evaluate := func(radiant, dire []int) float64 { return float64(len(radiant)) / 10 }
+
+	session := counterSession(t, PhasePick)
+	session.BotSpeed = "slow"
+	clock := &steppingClock{now: time.Unix(0, 0), step: 100 * time.Millisecond}
+	session.clock = clock
+
+	hero := NewMCTSBot(evaluate).ChooseHero(session)
+	if hero <= 0 || !session.IsHeroAvailable(hero) {
+		t.Fatalf("mcts chose unavailable hero %d", hero)
+	}
+	// 6 секунд бюджета по 100 мс за вызов — около 60 обращений к часам.
+	if budget := int(searchBudget("slow")/clock.step) + 2; clock.calls == 0 || clock.calls > budget {
+		t.Fatalf("session clock was read %d times, want 1..%d", clock.calls, budget)
+	}
+}
+
+func TestMCTSStopsOnStoppedClock(t *testing.T) {
+	session := counterSession(t, PhasePick)
+	session.clock = NewFakeClock(time.Unix(0, 0))
+
+	bot := NewMCTSBot(func(radiant, dire []int) float64 { return 0.5 })
+	if hero := bot.ChooseHero(session); hero <= 0 {
+		t.Fatalf("mcts on a stopped clock chose %d", hero)
+	}
+}
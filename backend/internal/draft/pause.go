@@ -0,0 +1,146 @@
+package draft
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PauseInfo — активная пауза сессии.
+type PauseInfo struct {
+	// Сторона, чей капитан поставил паузу; пустая — пауза тренера
+	// (или сессии без лобби), она расходует бюджет тренера, а не команд
+	By    Side      `json:"by,omitempty"`
+	Since time.Time `json:"since"`
+}
+
+// Pause ставит сессию на паузу: таймер и резервы замирают, отложенные ходы
+// бота отменяются, а в журнал пишется EventPaused. Если в лобби кто-то есть,
+// паузу может поставить только капитан или тренер. При заданном PauseBudget
+// пауза капитана расходует бюджет его стороны, а остальные — бюджет тренера.
+func (s *Store) Pause(id, token string) (*DraftSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if session.Completed {
+		return nil, errors.New("draft is already completed")
+	}
+	if session.Pause != nil {
+		return nil, errors.New("draft is already paused")
+	}
+
+	member, err := session.pauser(token)
+	if err != nil {
+		return nil, err
+	}
+	by := member.Side
+	if session.PauseBudget > 0 && *session.pauseLeft(by) <= 0 {
+		return nil, fmt.Errorf("%w: %s has no pause time left", ErrForbidden, pauseOwner(by))
+	}
+
+	now := s.clock.Now()
+	session.refresh(now)
+	session.Pause = &PauseInfo{By: by, Since: now.UTC()}
+	session.recordPause(EventPaused, by, member.Name, now)
+	session.revision++
+
+	fmt.Printf("[PAUSE] Draft %s paused by %s at step %d (%d sec left)\n",
+		id, pauseOwner(by), session.Step, session.CurrentTimer)
+
+	s.persist(session)
+	s.publish(session, EventPaused)
+	return s.view(session), nil
+}
+
+// Resume снимает паузу и возвращает ход боту, если сейчас его очередь.
+// Права те же, что у Pause: при непустом лобби — любой капитан или тренер.
+func (s *Store) Resume(id, token string) (*DraftSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if session.Pause == nil {
+		return nil, errors.New("draft is not paused")
+	}
+	member, err := session.pauser(token)
+	if err != nil {
+		return nil, err
+	}
+
+	s.resume(session, member.Name)
+	return s.view(session), nil
+}
+
+// resume снимает паузу: дедлайн хода сдвигается на её длительность, а
+// бюджет стороны, поставившей паузу, уменьшается. actor — кто снял паузу
+// (пустой — она кончилась сама). Вызывается под s.mu.
+func (s *Store) resume(session *DraftSession, actor string) {
+	now := s.clock.Now()
+	paused := now.Sub(session.Pause.Since)
+	if paused < 0 {
+		paused = 0
+	}
+	fmt.Printf("[PAUSE] Draft %s resumed after %v\n", session.ID, paused.Round(time.Second))
+
+	if session.PauseBudget > 0 {
+		left := session.pauseLeft(session.Pause.By)
+		*left = max(*left-ceilSeconds(paused), 0)
+	}
+	session.recordPause(EventResumed, session.Pause.By, actor, now)
+	session.Pause = nil
+	session.shift(paused)
+	session.refresh(now)
+	s.persist(session)
+	s.publish(session, EventResumed)
+	s.scheduleBotMove(session)
+}
+
+// pauseEnds — когда у поставившего паузу кончится бюджет.
+// false — бюджет не ограничен.
+func (s *DraftSession) pauseEnds() (time.Time, bool) {
+	if s.PauseBudget <= 0 {
+		return time.Time{}, false
+	}
+	return s.Pause.Since.Add(time.Duration(*s.pauseLeft(s.Pause.By)) * time.Second), true
+}
+
+// pauser проверяет право владельца токена на паузу и возвращает его.
+// Пауза записывается на сторону участника: у тренера её нет, и его пауза
+// расходует отдельный бюджет тренера. Пока лобби пусто, пауза доступна всем.
+func (s *DraftSession) pauser(token string) (LobbyMember, error) {
+	if len(s.Lobby) == 0 {
+		return LobbyMember{}, nil
+	}
+	member, ok := s.member(token)
+	if !ok || (member.Role != LobbyCaptain && member.Role != LobbyCoach) {
+		return LobbyMember{}, fmt.Errorf("%w: only captains and coaches can pause the draft", ErrForbidden)
+	}
+	return member, nil
+}
+
+// pauseLeft возвращает указатель на остаток бюджета пауз стороны;
+// пустая сторона — бюджет тренера.
+func (s *DraftSession) pauseLeft(side Side) *int {
+	switch side {
+	case SideRadiant:
+		return &s.PauseRadiant
+	case SideDire:
+		return &s.PauseDire
+	default:
+		return &s.PauseCoach
+	}
+}
+
+func pauseOwner(by Side) string {
+	if by == "" {
+		return "coach"
+	}
+	return string(by)
+}
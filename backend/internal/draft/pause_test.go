@@ -0,0 +1,162 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCoachCanPause(t *testing.T) {
+	store, session := newHumanSession(t)
+	teammate := join(t, store, session.ID, "Boxi", LobbyTeammate, SideRadiant)
+	coach := joinCoach(t, store, session, "Puppey")
+
+	if _, err := store.Pause(session.ID, teammate.Token); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("teammate pause: err = %v, want ErrForbidden", err)
+	}
+	paused, err := store.Pause(session.ID, coach.Token)
+	if err != nil {
+		t.Fatalf("coach pause: %v", err)
+	}
+	if paused.Pause == nil || paused.Pause.By != "" {
+		t.Fatalf("coach pause should not be charged to a side: %+v", paused.Pause)
+	}
+	if _, err := store.Resume(session.ID, coach.Token); err != nil {
+		t.Fatalf("coach resume: %v", err)
+	}
+}
+
+func TestPauseIsLoggedAndReplayed(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	store := NewStoreWithConfig(StoreConfig{Clock: clock})
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	step := func(d time.Duration, do func() error) {
+		t.Helper()
+		clock.Advance(d)
+		if err := do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	action := func() error {
+		current, err := store.GetSession(session.ID)
+		if err != nil {
+			return err
+		}
+		return act(store, current, "")
+	}
+	pause := func() error { _, err := store.Pause(session.ID, ""); return err }
+	resume := func() error { _, err := store.Resume(session.ID, ""); return err }
+
+	// Dire думает 5 секунд чистого времени: 3 до паузы и 2 после минутной паузы.
+	step(5*time.Second, action)
+	step(3*time.Second, pause)
+	step(time.Minute, resume)
+	step(2*time.Second, action)
+
+	final, err := store.GetSession(session.ID)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	var types []EventType
+	for _, e := range final.Events {
+		types = append(types, e.Type)
+	}
+	want := []EventType{"", EventPaused, EventResumed, ""}
+	if len(types) != len(want) {
+		t.Fatalf("journal types = %q, want %q", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("journal types = %q, want %q", types, want)
+		}
+	}
+	if got := len(final.Actions()); got != 2 {
+		t.Fatalf("Actions() = %d, want 2", got)
+	}
+
+	replay, err := NewReplay(final)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	paused, last := -1, -1
+	for i, f := range replay.Frames {
+		if f.Type == EventPaused {
+			paused = i
+		}
+		if f.Type == EventTimer && f.OffsetMs > 8000 && f.OffsetMs < 68000 {
+			t.Fatalf("timer tick during the pause at %d ms", f.OffsetMs)
+		}
+		if f.Action != nil && f.Action.Step == 1 {
+			last = i - 1
+		}
+	}
+	if paused < 0 || !replay.Frames[paused].State.Paused {
+		t.Fatal("replay has no paused frame")
+	}
+	if last < 0 {
+		t.Fatal("replay has no frame for the dire action")
+	}
+	tick := replay.Frames[last]
+	if wantTimer := final.Order[1].Timer - 4; tick.OffsetMs != 69000 || tick.State.CurrentTimer != wantTimer {
+		t.Fatalf("tick before the dire action: %d ms, timer %d, want 69000 ms, timer %d",
+			tick.OffsetMs, tick.State.CurrentTimer, wantTimer)
+	}
+	if tick.State.ReserveDireMs != final.ReserveDireMs {
+		t.Fatalf("dire reserve in replay = %d ms, want %d ms untouched",
+			tick.State.ReserveDireMs, final.ReserveDireMs)
+	}
+}
+
+func TestCoachPauseIsChargedToCoachBudget(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	store := NewStoreWithConfig(StoreConfig{Clock: clock})
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+		PauseBudget: 10,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	captain := join(t, store, session.ID, "Miracle", LobbyCaptain, SideRadiant)
+	coach := joinCoach(t, store, session, "Puppey")
+
+	if _, err := store.Pause(session.ID, coach.Token); err != nil {
+		t.Fatalf("coach pause: %v", err)
+	}
+	clock.Advance(4 * time.Second)
+	resumed, err := store.Resume(session.ID, coach.Token)
+	if err != nil {
+		t.Fatalf("coach resume: %v", err)
+	}
+	if resumed.PauseCoach != 6 || resumed.PauseRadiant != 10 || resumed.PauseDire != 10 {
+		t.Fatalf("budgets after coach pause: coach %d, radiant %d, dire %d; want 6, 10, 10",
+			resumed.PauseCoach, resumed.PauseRadiant, resumed.PauseDire)
+	}
+
+	// Остаток бюджета кончается — пауза снимается сама.
+	if _, err := store.Pause(session.ID, coach.Token); err != nil {
+		t.Fatalf("second coach pause: %v", err)
+	}
+	runFor(clock, 6*time.Second)
+	current := getSession(t, store, session.ID)
+	if current.Pause != nil || current.PauseCoach != 0 {
+		t.Fatalf("coach pause should end with the budget: pause %+v, coach budget %d", current.Pause, current.PauseCoach)
+	}
+
+	if _, err := store.Pause(session.ID, coach.Token); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("pause without coach budget: err = %v, want ErrForbidden", err)
+	}
+	if _, err := store.Pause(session.ID, captain.Token); err != nil {
+		t.Fatalf("captain pause should use the team budget: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package draft
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+// drawPool выбирает size случайных героев, поровну распределяя их по
+// основному атрибуту; остаток достаётся случайным атрибутам.
+func drawPool(catalog []heroes.Hero, size int) []int {
+	if size >= len(catalog) {
+		pool := make([]int, 0, len(catalog))
+		for _, h := range catalog {
+			pool = append(pool, h.ID)
+		}
+		sort.Ints(pool)
+		return pool
+	}
+
+	byAttr := make(map[string][]int)
+	for _, h := range catalog {
+		byAttr[h.PrimaryAttr] = append(byAttr[h.PrimaryAttr], h.ID)
+	}
+	attrs := make([]string, 0, len(byAttr))
+	for attr, ids := range byAttr {
+		rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+	rand.Shuffle(len(attrs), func(i, j int) { attrs[i], attrs[j] = attrs[j], attrs[i] })
+
+	// Раздаём героев по кругу: каждый проход добавляет по одному герою
+	// каждого атрибута, пока пул не заполнится.
+	pool := make([]int, 0, size)
+	for len(pool) < size {
+		for _, attr := range attrs {
+			if len(pool) == size {
+				break
+			}
+			if ids := byAttr[attr]; len(ids) > 0 {
+				pool = append(pool, ids[0])
+				byAttr[attr] = ids[1:]
+			}
+		}
+	}
+	sort.Ints(pool)
+	return pool
+}
@@ -0,0 +1,108 @@
+package draft
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+// poolCatalog — count героев на каждый атрибут, id подряд начиная с 1.
+func poolCatalog(count map[string]int) []heroes.Hero {
+	var list []heroes.Hero
+	for _, attr := range []string{"str", "agi", "int", "all"} {
+		for i := 0; i < count[attr]; i++ {
+			list = append(list, hero(len(list)+1, attr, "Melee"))
+		}
+	}
+	return list
+}
+
+func TestDrawPoolIsDistinctAndBalanced(t *testing.T) {
+	tests := []struct {
+		name  string
+		count map[string]int
+		size  int
+		// Сколько героев каждого атрибута допустимо в пуле
+		min, max map[string]int
+	}{
+		{
+			name:  "even attributes",
+			count: map[string]int{"str": 10, "agi": 10, "int": 10, "all": 10},
+			size:  27,
+			min:   map[string]int{"str": 6, "agi": 6, "int": 6, "all": 6},
+			max:   map[string]int{"str": 7, "agi": 7, "int": 7, "all": 7},
+		},
+		{
+			// Редкий атрибут отдаёт всех героев, остальные добирают пул
+			name:  "short attribute",
+			count: map[string]int{"str": 10, "agi": 10, "int": 10, "all": 2},
+			size:  20,
+			min:   map[string]int{"str": 6, "agi": 6, "int": 6, "all": 2},
+			max:   map[string]int{"str": 6, "agi": 6, "int": 6, "all": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := poolCatalog(tt.count)
+			attrOf := make(map[int]string, len(catalog))
+			for _, h := range catalog {
+				attrOf[h.ID] = h.PrimaryAttr
+			}
+
+			for run := 0; run < 50; run++ {
+				pool := drawPool(catalog, tt.size)
+				if len(pool) != tt.size {
+					t.Fatalf("pool of %d heroes, want %d", len(pool), tt.size)
+				}
+				seen := make(map[int]bool, len(pool))
+				got := make(map[string]int)
+				for i, id := range pool {
+					if seen[id] || attrOf[id] == "" || (i > 0 && pool[i-1] > id) {
+						t.Fatalf("pool %v: duplicate, unknown or unsorted hero %d", pool, id)
+					}
+					seen[id] = true
+					got[attrOf[id]]++
+				}
+				for attr := range tt.count {
+					if got[attr] < tt.min[attr] || got[attr] > tt.max[attr] {
+						t.Fatalf("pool %v: %d %s heroes, want %d..%d",
+							pool, got[attr], attr, tt.min[attr], tt.max[attr])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDrawPoolSmallCatalog(t *testing.T) {
+	catalog := []heroes.Hero{hero(3, "str", "Melee"), hero(1, "agi", "Ranged"), hero(2, "int", "Ranged")}
+	if pool := drawPool(catalog, 5); !reflect.DeepEqual(pool, []int{1, 2, 3}) {
+		t.Fatalf("pool %v, want the whole catalog", pool)
+	}
+}
+
+func TestCaptainsDraftSessionGetsPool(t *testing.T) {
+	catalog := testCatalog(t, poolCatalog(map[string]int{"str": 10, "agi": 10, "int": 10, "all": 10})...)
+	store := NewStoreWithConfig(StoreConfig{Catalog: catalog})
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+		Format:      "cd",
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	format, _ := LookupFormat("cd")
+	if len(session.Pool) != format.PoolSize {
+		t.Fatalf("pool of %d heroes, want %d", len(session.Pool), format.PoolSize)
+	}
+	for _, h := range catalog.All() {
+		if session.IsHeroAvailable(h.ID) != session.InPool(h.ID) {
+			t.Fatalf("hero %d: available %v, in pool %v",
+				h.ID, session.IsHeroAvailable(h.ID), session.InPool(h.ID))
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package draft
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReplayFrame — кадр повтора: событие драфта со смещением от его начала.
+type ReplayFrame struct {
+	OffsetMs int64        `json:"offsetMs"`
+	Type     EventType    `json:"type"`
+	State    SessionState `json:"state"`
+	Action   *DraftEvent  `json:"action,omitempty"`
+}
+
+// Replay — драфт, разложенный на кадры с исходными интервалами: посекундные
+// тики таймера, начало резерва, ходы, паузы и завершение.
+type Replay struct {
+	SessionID  string        `json:"sessionId"`
+	Format     string        `json:"format"`
+	StartedAt  time.Time     `json:"startedAt"`
+	DurationMs int64         `json:"durationMs"`
+	Completed  bool          `json:"completed"`
+	Frames     []ReplayFrame `json:"frames"`
+}
+
+// NewReplay строит повтор по журналу сессии. Таймер и резерв между ходами
+// восстанавливаются по времени событий, как их видели игроки; на паузах
+// из журнала время хода стоит.
+func NewReplay(session *DraftSession) (Replay, error) {
+	format, ok := formats[session.Format]
+	if !ok {
+		return Replay{}, fmt.Errorf("%w: %q", ErrUnknownFormat, session.Format)
+	}
+
+	cur := session.blank(format)
+	start := session.CreatedAt
+	if actions := session.Actions(); start.IsZero() && len(actions) > 0 {
+		first := actions[0]
+		start = first.At.Add(-time.Duration(cur.CurrentTimer-first.Timer) * time.Second)
+	}
+
+	replay := Replay{
+		SessionID: session.ID,
+		Format:    session.Format,
+		StartedAt: start,
+		Completed: session.Completed,
+	}
+	frame := func(at time.Time, eventType EventType, action *DraftEvent) {
+		replay.Frames = append(replay.Frames, ReplayFrame{
+			OffsetMs: at.Sub(start).Milliseconds(),
+			Type:     eventType,
+			State:    cur.State(),
+			Action:   action,
+		})
+	}
+
+	// Тики идут от начала хода с шагом в секунду; пауза сдвигает их
+	// вместе с дедлайном хода, так что время на паузе не тратит таймер.
+	beginTurn := func(at time.Time) {
+		cur.TurnDeadline = at.Add(time.Duration(cur.Order[cur.Step].Timer) * time.Second)
+		cur.usingReserve = false
+		cur.refresh(at)
+	}
+	beginTurn(start)
+	frame(start, EventTimer, nil)
+	tick := start
+	for i := range session.Events {
+		e := session.Events[i]
+
+		// Тики таймера до события: сначала основной таймер, затем резерв.
+		// Дольше таймера с резервом ход длиться не мог — больший разрыв
+		// (например, ответвление от старой сессии) не разворачивается в тики.
+		if cur.Pause == nil && !cur.Completed {
+			for at := tick.Add(time.Second); at.Before(e.At) && !at.After(cur.expiresAt()); at = at.Add(time.Second) {
+				cur.refresh(at)
+				if !cur.usingReserve && cur.overtime(at) > 0 && *cur.bank(cur.Side) > 0 {
+					cur.usingReserve = true
+					frame(at, EventReserveStarted, nil)
+				}
+				frame(at, EventTimer, nil)
+				tick = at
+			}
+		}
+
+		switch e.Type {
+		case EventPaused:
+			cur.refresh(e.At)
+			cur.Pause = &PauseInfo{By: e.Side, Since: e.At}
+			frame(e.At, EventPaused, &e)
+
+		case EventResumed:
+			if cur.Pause != nil {
+				paused := max(e.At.Sub(cur.Pause.Since), 0)
+				cur.Pause = nil
+				cur.shift(paused)
+				tick = tick.Add(paused)
+			}
+			cur.refresh(e.At)
+			frame(e.At, EventResumed, &e)
+
+		default:
+			if err := cur.replay(e); err != nil {
+				return Replay{}, err
+			}
+			eventType := EventAction
+			if e.Source == SourceAuto {
+				eventType = EventAutoAction
+			}
+			if !cur.Completed {
+				beginTurn(e.At)
+			}
+			frame(e.At, eventType, &e)
+			if cur.Completed {
+				frame(e.At, EventCompleted, nil)
+			}
+			tick = e.At
+		}
+	}
+
+	replay.DurationMs = replay.Frames[len(replay.Frames)-1].OffsetMs
+	return replay, nil
+}
+
+// FrameIndex возвращает индекс первого кадра, с которого начинается ход step.
+// Шаг за концом драфта даёт последний кадр.
+func (r Replay) FrameIndex(step int) int {
+	for i, f := range r.Frames {
+		if f.State.Step >= step {
+			return i
+		}
+	}
+	return len(r.Frames) - 1
+}
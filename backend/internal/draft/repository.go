@@ -0,0 +1,103 @@
+package draft
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrRecordNotFound возвращается репозиторием, если сессии нет.
+var ErrRecordNotFound = errors.New("session record not found")
+
+// SessionRepository — постоянное хранилище сессий за Store.
+// Store сохраняет сессию при создании, после каждого хода и периодически
+// из таймера; при старте незавершённые сессии читаются обратно.
+type SessionRepository interface {
+	Save(record SessionRecord) error
+	Load(id string) (SessionRecord, error)
+	List() ([]SessionRecord, error)
+}
+
+// SessionRecord — сериализуемый снимок сессии со всем, что нужно для её
+// восстановления, включая токены лобби, которые в JSON сессии не попадают.
+type SessionRecord struct {
+	Session  DraftSession `json:"session"`
+	Tokens   []string     `json:"tokens,omitempty"`
+	CoachKey string       `json:"coachKey,omitempty"`
+	SavedAt  time.Time    `json:"savedAt"`
+}
+
+// newRecord снимает запись с сессии на момент now. Вызывается под s.mu.
+func newRecord(session *DraftSession, now time.Time) SessionRecord {
+	record := SessionRecord{Session: session.Clone(), CoachKey: session.CoachKey, SavedAt: now.UTC()}
+	for _, m := range session.Lobby {
+		record.Tokens = append(record.Tokens, m.Token)
+	}
+	return record
+}
+
+// restore собирает из записи рабочую сессию (без бота — его создаёт Store).
+func (r SessionRecord) restore() *DraftSession {
+	session := r.Session.Clone()
+	session.taken = make(map[int]struct{})
+	for _, team := range []Team{session.Radiant, session.Dire} {
+		for _, h := range team.Bans {
+			session.taken[h] = struct{}{}
+		}
+		for _, h := range team.Picks {
+			session.taken[h] = struct{}{}
+		}
+	}
+	session.CoachKey = r.CoachKey
+	for i := range session.Lobby {
+		if i < len(r.Tokens) {
+			session.Lobby[i].Token = r.Tokens[i]
+		}
+	}
+	return &session
+}
+
+// MemoryRepository — SessionRepository в памяти процесса (данные теряются
+// при перезапуске).
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	records map[string]SessionRecord
+}
+
+// NewMemoryRepository создаёт пустой MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{records: make(map[string]SessionRecord)}
+}
+
+// Save сохраняет запись.
+func (r *MemoryRepository) Save(record SessionRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[record.Session.ID] = record
+	return nil
+}
+
+// Load возвращает запись по id.
+func (r *MemoryRepository) Load(id string) (SessionRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	record, ok := r.records[id]
+	if !ok {
+		return SessionRecord{}, ErrRecordNotFound
+	}
+	return record, nil
+}
+
+// List возвращает все записи, от старых к новым.
+func (r *MemoryRepository) List() ([]SessionRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]SessionRecord, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].SavedAt.Before(records[j].SavedAt) })
+	return records, nil
+}
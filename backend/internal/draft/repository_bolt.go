@@ -0,0 +1,84 @@
+package draft
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var sessionsBucket = []byte("sessions")
+
+// BoltRepository — SessionRepository во встроенной базе bbolt (один файл,
+// без внешнего сервера). Сессии хранятся как JSON-записи по id.
+type BoltRepository struct {
+	db *bolt.DB
+}
+
+// OpenBoltRepository открывает (или создаёт) файл базы.
+func OpenBoltRepository(path string) (*BoltRepository, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(sessionsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltRepository{db: db}, nil
+}
+
+// Close закрывает базу.
+func (r *BoltRepository) Close() error {
+	return r.db.Close()
+}
+
+// Save сохраняет запись.
+func (r *BoltRepository) Save(record SessionRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).Put([]byte(record.Session.ID), data)
+	})
+}
+
+// Load возвращает запись по id.
+func (r *BoltRepository) Load(id string) (SessionRecord, error) {
+	var record SessionRecord
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(sessionsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrRecordNotFound
+		}
+		return json.Unmarshal(data, &record)
+	})
+	return record, err
+}
+
+// List возвращает все записи, от старых к новым.
+func (r *BoltRepository) List() ([]SessionRecord, error) {
+	var records []SessionRecord
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).ForEach(func(_, data []byte) error {
+			var record SessionRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].SavedAt.Before(records[j].SavedAt) })
+	return records, nil
+}
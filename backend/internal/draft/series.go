@@ -0,0 +1,372 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// FearlessMode — какие герои прошлых игр серии недоступны в следующих.
+type FearlessMode string
+
+const (
+	// FearlessOff — обычная серия без ограничений.
+	FearlessOff FearlessMode = "off"
+	// FearlessTeam — команда не может снова пикать своих героев.
+	FearlessTeam FearlessMode = "team"
+	// FearlessBoth — герой, сыгранный любой командой, закрыт для обеих.
+	FearlessBoth FearlessMode = "both"
+)
+
+// SideRule — как распределяются сторона и первый пик в следующей игре.
+type SideRule string
+
+const (
+	// SideRuleAlternate — команды каждую игру меняются сторонами и первым пиком.
+	SideRuleAlternate SideRule = "alternate"
+	// SideRuleLoserChooses — проигравший выбирает сторону или первый пик.
+	// По умолчанию он берёт первый пик, а победитель — Radiant.
+	SideRuleLoserChooses SideRule = "loser_chooses"
+)
+
+var (
+	// ErrSeriesNotFound возвращается для неизвестного id серии.
+	ErrSeriesNotFound = errors.New("series not found")
+	// ErrInvalidSeries — ошибка в параметрах серии или следующей игры.
+	ErrInvalidSeries = errors.New("invalid series request")
+)
+
+// SeriesGame — одна игра серии.
+type SeriesGame struct {
+	SessionID string `json:"sessionId"`
+	Radiant   string `json:"radiant"`
+	Dire      string `json:"dire"`
+	FirstPick string `json:"firstPick"`
+	Winner    string `json:"winner,omitempty"`
+}
+
+// GameResult — исход сыгранной игры: драфт и сторона, которая победила.
+type GameResult struct {
+	SessionID string `json:"sessionId"`
+	SeriesID  string `json:"seriesId"`
+	Winner    Side   `json:"winner"`
+}
+
+// Series — серия Bo1/Bo3/Bo5 из нескольких драфтов между двумя командами.
+type Series struct {
+	ID        string         `json:"id"`
+	BestOf    int            `json:"bestOf"`
+	Teams     [2]string      `json:"teams"`
+	Fearless  FearlessMode   `json:"fearless"`
+	SideRule  SideRule       `json:"sideRule"`
+	Wins      map[string]int `json:"wins"`
+	Games     []SeriesGame   `json:"games"`
+	Completed bool           `json:"completed"`
+	Winner    string         `json:"winner,omitempty"`
+
+	// Параметры драфтов серии
+	BotTeam  string `json:"botTeam,omitempty"`
+	BotSpeed string `json:"botSpeed"`
+	BotType  string `json:"botType"`
+	Format   string `json:"format"`
+	// Патч всех игр серии
+	Patch string `json:"patch,omitempty"`
+	// Бюджет пауз команды на каждую игру в секундах; 0 — без ограничения
+	PauseBudget int `json:"pauseBudget,omitempty"`
+	// Ключ тренера всех игр серии; выдаётся только создателю серии
+	CoachKey string `json:"-"`
+}
+
+// SeriesConfig — параметры новой серии.
+type SeriesConfig struct {
+	TeamA, TeamB string
+	BestOf       int
+	Fearless     FearlessMode
+	SideRule     SideRule
+	// Команда на стороне Radiant в первой игре; пустая — TeamA
+	Radiant string
+	// Команда с первым пиком в первой игре; пустая — жребий
+	FirstPick string
+	// Команда бота; пустая — TeamB
+	BotTeam  string
+	BotSpeed string
+	BotType  string
+	Format   string
+	// Версия патча; пустая — последний патч каталога
+	Patch string
+	// Бюджет пауз команды на каждую игру в секундах; 0 — без ограничения
+	PauseBudget int
+}
+
+// NextGameRequest — результат текущей игры и выбор на следующую.
+type NextGameRequest struct {
+	// Победившая команда текущей игры (обязательно)
+	Winner string
+	// Необязательный выбор при SideRuleLoserChooses
+	Radiant   string
+	FirstPick string
+}
+
+// CreateSeries создаёт серию и первую игру в ней.
+func (s *Store) CreateSeries(ctx context.Context, cfg SeriesConfig) (*Series, error) {
+	if cfg.TeamA == "" || cfg.TeamB == "" || cfg.TeamA == cfg.TeamB {
+		return nil, fmt.Errorf("%w: two different team names are required", ErrInvalidSeries)
+	}
+	switch cfg.BestOf {
+	case 0:
+		cfg.BestOf = 3
+	case 1, 3, 5:
+	default:
+		return nil, fmt.Errorf("%w: bestOf must be 1, 3 or 5", ErrInvalidSeries)
+	}
+	switch cfg.Fearless {
+	case "":
+		cfg.Fearless = FearlessOff
+	case FearlessOff, FearlessTeam, FearlessBoth:
+	default:
+		return nil, fmt.Errorf("%w: unknown fearless mode %q", ErrInvalidSeries, cfg.Fearless)
+	}
+	switch cfg.SideRule {
+	case "":
+		cfg.SideRule = SideRuleLoserChooses
+	case SideRuleAlternate, SideRuleLoserChooses:
+	default:
+		return nil, fmt.Errorf("%w: unknown side rule %q", ErrInvalidSeries, cfg.SideRule)
+	}
+
+	series := &Series{
+		ID:       generateID(),
+		BestOf:   cfg.BestOf,
+		Teams:    [2]string{cfg.TeamA, cfg.TeamB},
+		Fearless: cfg.Fearless,
+		SideRule: cfg.SideRule,
+		Wins:     map[string]int{cfg.TeamA: 0, cfg.TeamB: 0},
+		BotTeam:  cfg.BotTeam,
+		BotSpeed: cfg.BotSpeed,
+		BotType:  cfg.BotType,
+		Format:   cfg.Format,
+		Patch:    cfg.Patch,
+
+		PauseBudget: cfg.PauseBudget,
+		CoachKey:    generateID() + generateID(),
+	}
+	if series.BotTeam == "" {
+		series.BotTeam = cfg.TeamB
+	}
+	if !series.hasTeam(series.BotTeam) {
+		return nil, fmt.Errorf("%w: unknown bot team %q", ErrInvalidSeries, series.BotTeam)
+	}
+
+	radiant := cfg.Radiant
+	if radiant == "" {
+		radiant = cfg.TeamA
+	}
+	firstPick := cfg.FirstPick
+	if firstPick == "" {
+		firstPick = series.Teams[rand.Intn(2)]
+	}
+	if !series.hasTeam(radiant) || !series.hasTeam(firstPick) {
+		return nil, fmt.Errorf("%w: radiant and firstPick must name a series team", ErrInvalidSeries)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.startSeriesGame(series, radiant, firstPick); err != nil {
+		return nil, err
+	}
+	s.series[series.ID] = series
+
+	fmt.Printf("[SERIES] New Bo%d %s: %s vs %s (fearless=%s, sides=%s)\n",
+		series.BestOf, series.ID, cfg.TeamA, cfg.TeamB, series.Fearless, series.SideRule)
+	return series.clone(), nil
+}
+
+// GetSeries возвращает состояние серии.
+func (s *Store) GetSeries(id string) (*Series, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series, ok := s.series[id]
+	if !ok {
+		return nil, ErrSeriesNotFound
+	}
+	return series.clone(), nil
+}
+
+// NextGame фиксирует победителя текущей игры и, если серия не решена,
+// создаёт следующую игру по правилам серии.
+func (s *Store) NextGame(id string, req NextGameRequest) (*Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.series[id]
+	if !ok {
+		return nil, ErrSeriesNotFound
+	}
+	if series.Completed {
+		return nil, fmt.Errorf("%w: series is already completed", ErrInvalidSeries)
+	}
+	if !series.hasTeam(req.Winner) {
+		return nil, fmt.Errorf("%w: winner must name a series team", ErrInvalidSeries)
+	}
+
+	// Берём копию: startSeriesGame дописывает в series.Games, и указатель
+	// на элемент среза мог бы устареть.
+	index := len(series.Games) - 1
+	current := series.Games[index]
+	if session, ok := s.sessions[current.SessionID]; ok && !session.Completed {
+		return nil, fmt.Errorf("%w: draft of game %d is not completed", ErrInvalidSeries, index+1)
+	}
+
+	// Сначала проверяем запрос и создаём следующую игру, и только потом
+	// записываем результат: при ошибке серия остаётся нетронутой.
+	decided := series.Wins[req.Winner]+1 > series.BestOf/2
+	if !decided {
+		loser := series.opponent(req.Winner)
+		var radiant, firstPick string
+		switch series.SideRule {
+		case SideRuleAlternate:
+			radiant = current.Dire
+			firstPick = series.opponent(current.FirstPick)
+		default:
+			radiant, firstPick = req.Winner, loser
+			if req.Radiant != "" {
+				radiant = req.Radiant
+			}
+			if req.FirstPick != "" {
+				firstPick = req.FirstPick
+			}
+		}
+		if !series.hasTeam(radiant) || !series.hasTeam(firstPick) {
+			return nil, fmt.Errorf("%w: radiant and firstPick must name a series team", ErrInvalidSeries)
+		}
+		if err := s.startSeriesGame(series, radiant, firstPick); err != nil {
+			return nil, err
+		}
+	}
+
+	series.Games[index].Winner = req.Winner
+	series.Wins[req.Winner]++
+	fmt.Printf("[SERIES] %s won game %d of %s\n", req.Winner, index+1, series.ID)
+
+	result := GameResult{SessionID: current.SessionID, SeriesID: series.ID, Winner: SideDire}
+	if current.Radiant == req.Winner {
+		result.Winner = SideRadiant
+	}
+	if handlers := s.results; len(handlers) > 0 {
+		go func() {
+			for _, fn := range handlers {
+				fn(result)
+			}
+		}()
+	}
+
+	if decided {
+		series.Completed = true
+		series.Winner = req.Winner
+		fmt.Printf("[SERIES] %s won series %s\n", req.Winner, series.ID)
+	}
+	return series.clone(), nil
+}
+
+// startSeriesGame создаёт драфт очередной игры с учётом fearless-ограничений.
+// Вызывается под s.mu.
+func (s *Store) startSeriesGame(series *Series, radiant, firstPick string) error {
+	dire := series.opponent(radiant)
+	sideOf := func(team string) Side {
+		if team == radiant {
+			return SideRadiant
+		}
+		return SideDire
+	}
+
+	session, err := s.createSession(SessionConfig{
+		RadiantName: radiant,
+		DireName:    dire,
+		FirstPick:   sideOf(firstPick),
+		BotSide:     sideOf(series.BotTeam),
+		BotSpeed:    series.BotSpeed,
+		BotType:     series.BotType,
+		Format:      series.Format,
+		Patch:       series.Patch,
+		PauseBudget: series.PauseBudget,
+		CoachKey:    series.CoachKey,
+		Locked: map[Side][]int{
+			SideRadiant: s.lockedHeroes(series, radiant),
+			SideDire:    s.lockedHeroes(series, dire),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	series.Games = append(series.Games, SeriesGame{
+		SessionID: session.ID,
+		Radiant:   radiant,
+		Dire:      dire,
+		FirstPick: firstPick,
+	})
+	fmt.Printf("[SERIES] Game %d of %s: %s (radiant) vs %s, first pick %s\n",
+		len(series.Games), series.ID, radiant, dire, firstPick)
+	return nil
+}
+
+// lockedHeroes — герои, которых team не может пикать в следующей игре.
+// Вызывается под s.mu.
+func (s *Store) lockedHeroes(series *Series, team string) []int {
+	if series.Fearless != FearlessTeam && series.Fearless != FearlessBoth {
+		return nil
+	}
+
+	set := make(map[int]struct{})
+	for _, game := range series.Games {
+		session, ok := s.sessions[game.SessionID]
+		if !ok {
+			continue
+		}
+		for _, side := range []Side{SideRadiant, SideDire} {
+			owner := game.Radiant
+			picks := session.Radiant.Picks
+			if side == SideDire {
+				owner, picks = game.Dire, session.Dire.Picks
+			}
+			if series.Fearless == FearlessTeam && owner != team {
+				continue
+			}
+			for _, h := range picks {
+				set[h] = struct{}{}
+			}
+		}
+	}
+
+	locked := make([]int, 0, len(set))
+	for h := range set {
+		locked = append(locked, h)
+	}
+	sort.Ints(locked)
+	return locked
+}
+
+func (series *Series) hasTeam(name string) bool {
+	return name == series.Teams[0] || name == series.Teams[1]
+}
+
+func (series *Series) opponent(team string) string {
+	if team == series.Teams[0] {
+		return series.Teams[1]
+	}
+	return series.Teams[0]
+}
+
+func (series *Series) clone() *Series {
+	c := *series
+	c.Games = append([]SeriesGame(nil), series.Games...)
+	c.Wins = make(map[string]int, len(series.Wins))
+	for team, wins := range series.Wins {
+		c.Wins[team] = wins
+	}
+	return &c
+}
@@ -0,0 +1,120 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newSeriesStore(t *testing.T) (*Store, *Series) {
+	t.Helper()
+	store := NewStoreWithConfig(StoreConfig{Clock: NewFakeClock(time.Unix(0, 0))})
+	series, err := store.CreateSeries(context.Background(), SeriesConfig{
+		TeamA:     "Liquid",
+		TeamB:     "Spirit",
+		BestOf:    3,
+		FirstPick: "Liquid",
+	})
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+	return store, series
+}
+
+// finishGame помечает драфт текущей игры завершённым, не проходя его целиком.
+func finishGame(store *Store, series *Series) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.sessions[series.Games[len(series.Games)-1].SessionID].Completed = true
+}
+
+func TestNextGameRejectsInvalidChoiceWithoutRecording(t *testing.T) {
+	store, series := newSeriesStore(t)
+	finishGame(store, series)
+
+	_, err := store.NextGame(series.ID, NextGameRequest{Winner: "Liquid", FirstPick: "OG"})
+	if !errors.Is(err, ErrInvalidSeries) {
+		t.Fatalf("err = %v, want ErrInvalidSeries", err)
+	}
+
+	got, err := store.GetSeries(series.ID)
+	if err != nil {
+		t.Fatalf("get series: %v", err)
+	}
+	if got.Wins["Liquid"] != 0 || got.Games[0].Winner != "" || len(got.Games) != 1 {
+		t.Fatalf("failed request changed the series: wins=%v games=%+v", got.Wins, got.Games)
+	}
+
+	// Исправленный запрос проходит, и победа засчитывается ровно один раз.
+	got, err = store.NextGame(series.ID, NextGameRequest{Winner: "Liquid"})
+	if err != nil {
+		t.Fatalf("next game: %v", err)
+	}
+	if got.Wins["Liquid"] != 1 || got.Games[0].Winner != "Liquid" || len(got.Games) != 2 {
+		t.Fatalf("unexpected series after game 1: wins=%v games=%+v", got.Wins, got.Games)
+	}
+	if got.Games[1].Radiant != "Liquid" || got.Games[1].FirstPick != "Spirit" {
+		t.Fatalf("loser should get first pick: %+v", got.Games[1])
+	}
+}
+
+func TestNextGameCompletesSeries(t *testing.T) {
+	store, series := newSeriesStore(t)
+	for game := 1; game <= 2; game++ {
+		finishGame(store, series)
+		var err error
+		if series, err = store.NextGame(series.ID, NextGameRequest{Winner: "Spirit"}); err != nil {
+			t.Fatalf("game %d: %v", game, err)
+		}
+	}
+
+	if !series.Completed || series.Winner != "Spirit" || series.Wins["Spirit"] != 2 {
+		t.Fatalf("series not completed by Spirit: %+v", series)
+	}
+	if len(series.Games) != 2 || series.Games[1].Winner != "Spirit" {
+		t.Fatalf("decisive game should not start another draft: %+v", series.Games)
+	}
+	if _, err := store.NextGame(series.ID, NextGameRequest{Winner: "Spirit"}); !errors.Is(err, ErrInvalidSeries) {
+		t.Fatalf("completed series accepted another game: %v", err)
+	}
+}
+
+func TestNextGameReportsResult(t *testing.T) {
+	store, series := newSeriesStore(t)
+	results := make(chan GameResult, 2)
+	store.OnResult(func(r GameResult) { results <- r })
+
+	// Игра 1: Liquid — Radiant; игра 2: победитель Spirit берёт Radiant.
+	want := []Side{SideDire, SideRadiant}
+	for game := range want {
+		finishGame(store, series)
+		sessionID := series.Games[game].SessionID
+		var err error
+		if series, err = store.NextGame(series.ID, NextGameRequest{Winner: "Spirit"}); err != nil {
+			t.Fatalf("game %d: %v", game+1, err)
+		}
+		select {
+		case r := <-results:
+			if r.SessionID != sessionID || r.SeriesID != series.ID || r.Winner != want[game] {
+				t.Fatalf("game %d result %+v, want %s won %s", game+1, r, want[game], sessionID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("game %d: no result reported", game+1)
+		}
+	}
+}
+
+func TestSeriesGamesShareCoachKey(t *testing.T) {
+	store, series := newSeriesStore(t)
+	finishGame(store, series)
+	series, err := store.NextGame(series.ID, NextGameRequest{Winner: "Liquid"})
+	if err != nil {
+		t.Fatalf("next game: %v", err)
+	}
+	for i, game := range series.Games {
+		if _, err := store.Join(game.SessionID, "Puppey", LobbyCoach, "", series.CoachKey); err != nil {
+			t.Fatalf("game %d: coach with the series key: %v", i+1, err)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package draft
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/example/draftpractice/internal/heroes"
+)
 
 // Phase — категория текущего действия в драфте.
 type Phase string
@@ -27,38 +32,105 @@ type DraftSession struct {
 	Completed bool
 	Step      int
 	Order     []Turn
+	// Имя формата драфта (cm_7_34, cm_7_00, ...)
+	Format string `json:"format"`
+	// Патч, по правилам которого идёт драфт: герои вне пула Captains Mode
+	// патча недоступны. Пустой — сессия создана до версионирования каталога
+	Patch string `json:"patch,omitempty"`
+	// Время начала драфта (отсчёт для повтора)
+	CreatedAt time.Time `json:"createdAt"`
+	// Пул героев Captains Draft; пустой — доступны все герои
+	Pool []int `json:"pool,omitempty"`
+	// Герои, которые сторона не может пикать (fearless-серии)
+	Locked map[Side][]int `json:"locked,omitempty"`
 
-	// Таймеры и резервы
-	CurrentTimer   int
-	ReserveRadiant int
-	ReserveDire    int
-	taken          map[int]struct{}
+	// Таймеры и резервы. Источник истины — дедлайн основного таймера хода
+	// и резерв сторон на начало хода; оставшееся время ниже (в секундах и
+	// миллисекундах) вычисляется из них при чтении.
+	TurnDeadline     time.Time   `json:"turnDeadline"`
+	ReserveBank      ReserveBank `json:"reserveBank"`
+	CurrentTimer     int
+	ReserveRadiant   int
+	ReserveDire      int
+	TimerMs          int64 `json:"timerMs"`
+	ReserveRadiantMs int64 `json:"reserveRadiantMs"`
+	ReserveDireMs    int64 `json:"reserveDireMs"`
+	taken            map[int]struct{}
+	// Текущий ход уже идёт за счёт резерва
+	usingReserve bool
 
 	// Кто получил первый пик
 	FirstPick Side `json:"firstPick"`
 	// Скорость бота
 	BotSpeed string `json:"botSpeed"`
-	// Какая сторона управляется ботом (radiant или dire)
-	BotSide Side `json:"botSide"`
+	// Какая сторона управляется ботом (radiant или dire); пустая — без бота
+	BotSide Side `json:"botSide,omitempty"`
+	// Тип бота из реестра Store (random, heuristic, ...)
+	BotType string `json:"botType"`
+	// Ожидаемое ботом продолжение драфта (если бот умеет его сообщать)
+	BotPlan []int `json:"botPlan,omitempty"`
+
+	// Журнал ходов в порядке их совершения (только дописывается)
+	Events []DraftEvent `json:"events"`
+
+	// Участники лобби: капитаны, тиммейты и зрители
+	Lobby []LobbyMember `json:"lobby"`
+
+	// Активная пауза; nil — драфт идёт
+	Pause *PauseInfo `json:"pause,omitempty"`
+	// Бюджет пауз каждой команды и тренера (0 — без ограничения) и его
+	// остаток в секундах. Из PauseCoach оплачиваются паузы тренера и паузы
+	// в сессии без лобби
+	PauseBudget  int `json:"pauseBudget,omitempty"`
+	PauseRadiant int `json:"pauseRadiant,omitempty"`
+	PauseDire    int `json:"pauseDire,omitempty"`
+	PauseCoach   int `json:"pauseCoach,omitempty"`
+
+	// Тренировочная сессия: разрешён откат ходов (undo)
+	Practice bool `json:"practice"`
+	// Ключ тренера: выдаётся только создателю сессии, без него войти в лобби
+	// тренером нельзя. В JSON сессии не попадает
+	CoachKey string `json:"-"`
+	// Исходная сессия и шаг, с которого эта сессия ответвлена (fork)
+	ForkedFrom string `json:"forkedFrom,omitempty"`
+	ForkStep   int    `json:"forkStep,omitempty"`
+
+	// Бот, который ходит за BotSide и делает автоходы по таймауту
+	bot Bot
+	// Часы Store; nil — системное время
+	clock Clock
+	// Каталог героев Store
+	catalog *heroes.Catalog
+	// Правила патча Patch, разрешённые по каталогу один раз — при создании
+	// или восстановлении сессии; nil — сессия без патча
+	patch *heroes.Patch
+	// Для сессии запущен runTimer
+	ticking bool
+	// Растёт при каждом откате и паузе: отложенные ходы бота и автоходы,
+	// начатые раньше, не применяются
+	revision int
 }
 
-// newDraftSession — инициализация новой сессии.
-func newDraftSession(id, radiantName, direName string, firstPick Side) *DraftSession {
+// newDraftSession — инициализация новой сессии по формату; первый ход
+// начинается в момент now.
+func newDraftSession(id, radiantName, direName string, firstPick Side, format Format, now time.Time) *DraftSession {
+	reserve := time.Duration(format.ReserveTime) * time.Second
 	s := &DraftSession{
-		ID:             id,
-		Radiant:        Team{Name: radiantName},
-		Dire:           Team{Name: direName},
-		Order:          schedule(firstPick),
-		Step:           0,
-		ReserveRadiant: ReserveTimeSeconds,
-		ReserveDire:    ReserveTimeSeconds,
-		taken:          make(map[int]struct{}),
-		FirstPick:      firstPick,
+		ID:          id,
+		Radiant:     Team{Name: radiantName},
+		Dire:        Team{Name: direName},
+		Order:       format.Schedule(firstPick),
+		Format:      format.Name,
+		CreatedAt:   now.UTC(),
+		Step:        0,
+		ReserveBank: ReserveBank{Radiant: reserve, Dire: reserve},
+		taken:       make(map[int]struct{}),
+		FirstPick:   firstPick,
 	}
 
 	s.Stage = s.Order[0].Phase
 	s.Side = s.Order[0].Side
-	s.CurrentTimer = s.Order[0].Timer
+	s.startTurn(now)
 	return s
 }
 
@@ -67,6 +139,9 @@ func (s *DraftSession) ApplyAction(heroID int) error {
 	if s.Completed {
 		return fmt.Errorf("draft already completed")
 	}
+	if s.Pause != nil {
+		return fmt.Errorf("draft is paused")
+	}
 
 	if heroID <= 0 {
 		return fmt.Errorf("invalid hero id %d", heroID)
@@ -74,6 +149,17 @@ func (s *DraftSession) ApplyAction(heroID int) error {
 	if _, exists := s.taken[heroID]; exists {
 		return fmt.Errorf("hero %d already selected", heroID)
 	}
+	if !s.IsHeroLegal(heroID) {
+		return fmt.Errorf("hero %d is not available in Captains Mode on patch %s", heroID, s.Patch)
+	}
+	if !s.InPool(heroID) {
+		return fmt.Errorf("hero %d is not in the draft pool", heroID)
+	}
+	if s.Stage == PhasePick && s.IsLocked(s.Side, heroID) {
+		return fmt.Errorf("hero %d was already played by %s in this series", heroID, s.Side)
+	}
+
+	s.spendReserve(s.now())
 
 	team := s.activeTeam()
 	switch s.Stage {
@@ -100,7 +186,7 @@ func (s *DraftSession) NextStep() {
 	next := s.Order[s.Step]
 	s.Stage = next.Phase
 	s.Side = next.Side
-	s.CurrentTimer = next.Timer
+	s.startTurn(s.now())
 }
 
 // activeTeam — возвращает команду, которая сейчас ходит.
@@ -111,6 +197,14 @@ func (s *DraftSession) activeTeam() *Team {
 	return &s.Dire
 }
 
+// opponentTeam — возвращает команду, которая сейчас ждёт своего хода.
+func (s *DraftSession) opponentTeam() *Team {
+	if s.Side == SideRadiant {
+		return &s.Dire
+	}
+	return &s.Radiant
+}
+
 // IsHeroUsed — проверяет, использовался ли герой.
 func (s *DraftSession) IsHeroUsed(heroID int) bool {
 	for _, h := range s.Radiant.Bans {
@@ -136,24 +230,135 @@ func (s *DraftSession) IsHeroUsed(heroID int) bool {
 	return false
 }
 
+// InPool — проверяет, входит ли герой в пул сессии: он разрешён патчем и,
+// если формат тянет пул, попал в него (без пула — любой разрешённый герой).
+func (s *DraftSession) InPool(heroID int) bool {
+	if !s.IsHeroLegal(heroID) {
+		return false
+	}
+	if len(s.Pool) == 0 {
+		return true
+	}
+	for _, h := range s.Pool {
+		if h == heroID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHeroLegal — есть ли герой в пуле Captains Mode патча сессии. У сессий
+// без патча ограничений нет.
+func (s *DraftSession) IsHeroLegal(heroID int) bool {
+	if s.patch == nil {
+		return true
+	}
+	return s.patch.InCM(heroID)
+}
+
+// pinPatch разрешает Patch по каталогу сессии и закрепляет результат.
+// Вызывается, когда у сессии появился каталог: при восстановлении из
+// репозитория (новая сессия получает патч сразу в createSession).
+func (s *DraftSession) pinPatch() error {
+	s.patch = nil
+	if s.Patch == "" || s.catalog == nil {
+		return nil
+	}
+	patch, err := s.catalog.Patch(s.Patch)
+	if err != nil {
+		return err
+	}
+	s.patch = &patch
+	return nil
+}
+
+// IsLocked — запрещён ли герой для пиков стороны side.
+func (s *DraftSession) IsLocked(side Side, heroID int) bool {
+	for _, h := range s.Locked[side] {
+		if h == heroID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHeroAvailable — можно ли сделать текущий ход этим героем.
+func (s *DraftSession) IsHeroAvailable(heroID int) bool {
+	if !s.InPool(heroID) || s.IsHeroUsed(heroID) {
+		return false
+	}
+	return s.Stage != PhasePick || !s.IsLocked(s.Side, heroID)
+}
+
+// AvailableHeroes — герои каталога, доступные для текущего хода.
+func (s *DraftSession) AvailableHeroes() []heroes.Hero {
+	all := s.catalogHeroes()
+	result := make([]heroes.Hero, 0, len(all))
+	for _, h := range all {
+		if s.IsHeroAvailable(h.ID) {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// catalogHeroes — герои каталога Store; у сессии без Store каталога нет.
+func (s *DraftSession) catalogHeroes() []heroes.Hero {
+	if s.catalog == nil {
+		return nil
+	}
+	return s.catalog.All()
+}
+
 // Clone — делает глубокую копию сессии.
 func (s *DraftSession) Clone() DraftSession {
 	copySession := DraftSession{
-		ID:             s.ID,
-		Stage:          s.Stage,
-		Side:           s.Side,
-		Completed:      s.Completed,
-		Step:           s.Step,
-		Order:          append([]Turn(nil), s.Order...),
-		CurrentTimer:   s.CurrentTimer,
-		ReserveRadiant: s.ReserveRadiant,
-		ReserveDire:    s.ReserveDire,
-		FirstPick:      s.FirstPick,
-		taken:          make(map[int]struct{}, len(s.taken)),
+		ID:               s.ID,
+		Stage:            s.Stage,
+		Side:             s.Side,
+		Completed:        s.Completed,
+		Step:             s.Step,
+		Order:            append([]Turn(nil), s.Order...),
+		Format:           s.Format,
+		Patch:            s.Patch,
+		CreatedAt:        s.CreatedAt,
+		Pool:             append([]int(nil), s.Pool...),
+		Locked:           cloneLocked(s.Locked),
+		Lobby:            append([]LobbyMember(nil), s.Lobby...),
+		Events:           append([]DraftEvent(nil), s.Events...),
+		TurnDeadline:     s.TurnDeadline,
+		ReserveBank:      s.ReserveBank,
+		CurrentTimer:     s.CurrentTimer,
+		ReserveRadiant:   s.ReserveRadiant,
+		ReserveDire:      s.ReserveDire,
+		TimerMs:          s.TimerMs,
+		ReserveRadiantMs: s.ReserveRadiantMs,
+		ReserveDireMs:    s.ReserveDireMs,
+		clock:            s.clock,
+		catalog:          s.catalog,
+		patch:            s.patch,
+		FirstPick:        s.FirstPick,
+		BotSpeed:         s.BotSpeed,
+		BotSide:          s.BotSide,
+		BotType:          s.BotType,
+		BotPlan:          append([]int(nil), s.BotPlan...),
+		PauseBudget:      s.PauseBudget,
+		PauseRadiant:     s.PauseRadiant,
+		PauseDire:        s.PauseDire,
+		PauseCoach:       s.PauseCoach,
+		Practice:         s.Practice,
+		CoachKey:         s.CoachKey,
+		ForkedFrom:       s.ForkedFrom,
+		ForkStep:         s.ForkStep,
+		taken:            make(map[int]struct{}, len(s.taken)),
 	}
 	for heroID := range s.taken {
 		copySession.taken[heroID] = struct{}{}
 	}
+	if s.Pause != nil {
+		pause := *s.Pause
+		copySession.Pause = &pause
+	}
 	copySession.Radiant = Team{
 		Name:  s.Radiant.Name,
 		Bans:  append([]int(nil), s.Radiant.Bans...),
@@ -166,3 +371,14 @@ func (s *DraftSession) Clone() DraftSession {
 	}
 	return copySession
 }
+
+func cloneLocked(locked map[Side][]int) map[Side][]int {
+	if locked == nil {
+		return nil
+	}
+	result := make(map[Side][]int, len(locked))
+	for side, ids := range locked {
+		result[side] = append([]int(nil), ids...)
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package draft
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSessionPatchIsPinnedAtCreation(t *testing.T) {
+	store := NewStoreWithConfig(StoreConfig{Clock: NewFakeClock(time.Unix(0, 0))})
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+		Patch:       "7.32e",
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.patch == nil || session.patch.Version != "7.32e" {
+		t.Fatalf("session patch = %+v, want 7.32e", session.patch)
+	}
+
+	// Правила берутся из закреплённого патча, а не из каталога на каждый вызов.
+	session.catalog = nil
+	if session.IsHeroLegal(138) {
+		t.Error("hero 138 is legal on 7.32e")
+	}
+	if !session.IsHeroLegal(1) {
+		t.Error("hero 1 is not legal on 7.32e")
+	}
+	clone := session.Clone()
+	if clone.IsHeroLegal(138) {
+		t.Error("clone lost the pinned patch")
+	}
+}
+
+func TestRestorePinsPatch(t *testing.T) {
+	repo := NewMemoryRepository()
+	first := NewStoreWithConfig(StoreConfig{Repository: repo, Clock: NewFakeClock(time.Unix(0, 0))})
+	session, err := first.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+		BotSide:     SideDire,
+		Patch:       "7.32e",
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	first.Flush()
+
+	second := NewStoreWithConfig(StoreConfig{Repository: repo, Clock: NewFakeClock(time.Unix(0, 0))})
+	if restored, err := second.Restore(); err != nil || restored != 1 {
+		t.Fatalf("restore: %d sessions, err %v", restored, err)
+	}
+	second.mu.RLock()
+	defer second.mu.RUnlock()
+	if patch := second.sessions[session.ID].patch; patch == nil || patch.Version != "7.32e" {
+		t.Fatalf("restored patch = %+v, want 7.32e", patch)
+	}
+}
@@ -6,201 +6,596 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"sync"
 	"math/rand"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/example/draftpractice/internal/heroes"
 )
 
-// Store хранит сессии в памяти.
+// ErrUnknownBot возвращается, если запрошен незарегистрированный тип бота.
+var ErrUnknownBot = errors.New("unknown bot type")
+
+// Store управляет активными сессиями: держит их в памяти вместе с таймерами
+// и ботами и сохраняет снимки в SessionRepository.
 type Store struct {
 	mu       sync.RWMutex
 	sessions map[string]*DraftSession
+	series   map[string]*Series
+	bots     map[string]BotFactory
+	events   *hub
+	repo     SessionRepository
+	writer   *sessionWriter
+	clock    Clock
+	catalog  *heroes.Catalog
+	// Обработчики завершённых драфтов (история, аналитика)
+	completed []func(DraftSession)
+	// Обработчики исходов игр, записанных в сериях
+	results []func(GameResult)
+}
+
+// StoreConfig — зависимости Store. Пустые поля заменяются значениями
+// по умолчанию: хранилищем в памяти, системными часами и встроенным
+// в бинарник снимком каталога героев.
+type StoreConfig struct {
+	Repository SessionRepository
+	Clock      Clock
+	Catalog    *heroes.Catalog
 }
 
-// NewStore создаёт новый Store.
+// persistEvery — как часто (в тиках таймера) сохранять идущую сессию
+// между ходами, чтобы после рестарта таймеры продолжились почти с того же места.
+const persistEvery = 5
+
+// NewStore создаёт новый Store в памяти со встроенными ботами random и heuristic.
 func NewStore() *Store {
-	return &Store{
+	return NewStoreWithConfig(StoreConfig{})
+}
+
+// NewStoreWithConfig создаёт Store с заданными хранилищем сессий, часами и каталогом героев.
+// Чтобы продолжить незавершённые сессии, после регистрации ботов вызовите Restore.
+func NewStoreWithConfig(cfg StoreConfig) *Store {
+	if cfg.Repository == nil {
+		cfg.Repository = NewMemoryRepository()
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock()
+	}
+	if cfg.Catalog == nil {
+		cfg.Catalog = heroes.SnapshotCatalog()
+	}
+	s := &Store{
 		sessions: make(map[string]*DraftSession),
+		series:   make(map[string]*Series),
+		bots:     make(map[string]BotFactory),
+		events:   newHub(),
+		repo:     cfg.Repository,
+		writer:   newSessionWriter(cfg.Repository),
+		clock:    cfg.Clock,
+		catalog:  cfg.Catalog,
 	}
+	s.RegisterBot(DefaultBotType, func() Bot { return RandomBot{} })
+	s.RegisterBot("heuristic", func() Bot { return HeuristicBot{} })
+	return s
+}
+
+// RegisterBot добавляет (или заменяет) тип бота в реестре.
+func (s *Store) RegisterBot(name string, factory BotFactory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bots[name] = factory
+}
+
+// OnCompleted добавляет обработчик, который получает копию каждой
+// завершённой сессии. Обработчики вызываются в отдельной горутине.
+func (s *Store) OnCompleted(fn func(DraftSession)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed = append(s.completed, fn)
+}
+
+// OnResult добавляет обработчик исходов игр: победитель драфта известен,
+// только когда его называют при переходе к следующей игре серии (NextGame).
+// Обработчики вызываются в отдельной горутине.
+func (s *Store) OnResult(fn func(GameResult)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, fn)
+}
+
+// BotTypes возвращает отсортированный список зарегистрированных ботов.
+func (s *Store) BotTypes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.bots))
+	for name := range s.bots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SessionConfig — параметры новой сессии.
+type SessionConfig struct {
+	RadiantName string
+	DireName    string
+	FirstPick   Side
+	// Какая сторона управляется ботом
+	BotSide  Side
+	BotSpeed string
+	// Тип бота из реестра; пустой — DefaultBotType
+	BotType string
+	// Имя формата; пустое — DefaultFormat
+	Format string
+	// Версия патча, например 7.32e; пустая — последний патч каталога
+	Patch string
+	// Герои, которые сторона не может пикать (fearless-серии)
+	Locked map[Side][]int
+	// Тренировочная сессия: разрешён откат ходов
+	Practice bool
+	// Бюджет пауз каждой команды в секундах; 0 — без ограничения
+	PauseBudget int
+	// Ключ тренера; пустой — сгенерировать новый (у серии он общий на все игры)
+	CoachKey string
 }
 
 // CreateSession создаёт новую сессию и запускает таймер.
-func (s *Store) CreateSession(
-	ctx context.Context,
-	radiantName, direName string,
-	firstPick Side,
-	botSide Side,
-	botSpeed string,
-) (*DraftSession, error) {
-	if len(heroes.All()) == 0 {
-		return nil, errors.New("hero cache is empty")
+func (s *Store) CreateSession(ctx context.Context, cfg SessionConfig) (*DraftSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, err := s.createSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(session), nil
+}
+
+// createSession регистрирует сессию и запускает её таймер. Вызывается под s.mu.
+func (s *Store) createSession(cfg SessionConfig) (*DraftSession, error) {
+	if len(s.catalog.All()) == 0 {
+		return nil, errors.New("hero catalog is empty")
+	}
+	patch, err := s.catalog.Patch(cfg.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.BotType == "" {
+		cfg.BotType = DefaultBotType
+	}
+
+	format, err := LookupFormat(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	factory, ok := s.bots[cfg.BotType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownBot, cfg.BotType)
 	}
 
 	id := generateID()
-	session := newDraftSession(id, radiantName, direName, firstPick)
-	session.BotSide = botSide
-	session.BotSpeed = botSpeed
+	session := newDraftSession(id, cfg.RadiantName, cfg.DireName, cfg.FirstPick, format, s.clock.Now())
+	session.clock = s.clock
+	session.catalog = s.catalog
+	session.Patch = patch.Version
+	session.patch = &patch
+	session.BotSide = cfg.BotSide
+	session.BotSpeed = cfg.BotSpeed
+	session.BotType = cfg.BotType
+	session.bot = factory()
+	session.Locked = cfg.Locked
+	session.Practice = cfg.Practice
+	session.CoachKey = cfg.CoachKey
+	if session.CoachKey == "" {
+		session.CoachKey = generateID() + generateID()
+	}
+	session.PauseBudget = cfg.PauseBudget
+	session.PauseRadiant = cfg.PauseBudget
+	session.PauseDire = cfg.PauseBudget
+	session.PauseCoach = cfg.PauseBudget
+	if format.PoolSize > 0 {
+		legal := make([]heroes.Hero, 0)
+		for _, h := range s.catalog.All() {
+			if patch.InCM(h.ID) {
+				legal = append(legal, h)
+			}
+		}
+		session.Pool = drawPool(legal, format.PoolSize)
+	}
 
-	s.mu.Lock()
 	s.sessions[id] = session
-	s.mu.Unlock()
+	s.persist(session)
 
-	fmt.Printf("[SESSION] New draft %s started: %s vs %s\n", id, radiantName, direName)
-	fmt.Printf("[SESSION] Bot: %s (%s)\n", botSide, botSpeed)
+	fmt.Printf("[SESSION] New draft %s started: %s vs %s (%s)\n",
+		id, cfg.RadiantName, cfg.DireName, format.Name)
+	fmt.Printf("[SESSION] Patch %s, bot: %s %s (%s)\n", session.Patch, cfg.BotSide, cfg.BotType, cfg.BotSpeed)
 	fmt.Printf("[SESSION] First move: %s %s (timer %d sec)\n",
 		session.Side, session.Stage, session.CurrentTimer)
 
 	// Запускаем фонового тикера для этой сессии.
-	go s.runTimer(session)
-
-	// Если первый пик принадлежит боту — он начинает сам
-	if session.FirstPick == session.BotSide {
-		go func() {
-			delay := botThinkDelay(botSpeed)
-			fmt.Printf("[BOT] starting draft: %s (%s) thinking for %v...\n",
-				session.BotSide, botSpeed, delay)
-			time.Sleep(delay)
+	s.startTimer(session)
 
-			s.mu.Lock()
-			defer s.mu.Unlock()
+	// Если первый ход принадлежит боту — он начинает сам
+	s.scheduleBotMove(session)
 
-			if session.Completed {
-				return
-			}
+	return session, nil
+}
 
-			hero := randomAvailableHero(session)
-			_ = session.ApplyAction(hero)
-			fmt.Printf("[BOT] %s starts with hero %d\n", session.BotSide, hero)
-			fmt.Printf("[NEXT] Now %s %s (timer %d sec)\n",
-				session.Side, session.Stage, session.CurrentTimer)
-		}()
+// startTimer запускает runTimer, если у сессии его ещё нет. Вызывается под s.mu.
+func (s *Store) startTimer(session *DraftSession) {
+	if session.ticking {
+		return
 	}
-
-	return session.ClonePtr(), nil
+	session.ticking = true
+	go s.runTimer(session)
 }
 
-// runTimer — отслеживает время хода и делает автоход при истечении.
+// runTimer — отслеживает время хода по часам Store: раз в секунду рассылает
+// подписчикам событие timer, объявляет начало резерва и делает автоход,
+// когда кончились и таймер, и резерв. Оставшееся время считается от
+// дедлайна, поэтому задержки пробуждений не накапливаются.
 func (s *Store) runTimer(session *DraftSession) {
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
+	ticks := 0
+	for {
 		s.mu.Lock()
 
 		if session.Completed {
+			session.ticking = false
 			s.mu.Unlock()
-			fmt.Printf("[SESSION] Draft %s completed.\n", session.ID)
 			return
 		}
 
-		session.CurrentTimer--
+		now := s.clock.Now()
+		wait := time.Second
+
+		switch {
+		case session.Pause != nil:
+			// На паузе таймер и резервы стоят; тикает только бюджет пауз
+			if until, ok := session.pauseEnds(); ok {
+				if !now.Before(until) {
+					fmt.Printf("[PAUSE] %s pause budget is over\n", pauseOwner(session.Pause.By))
+					s.resume(session, "")
+					wait = 0
+				} else {
+					wait = min(wait, until.Sub(now))
+				}
+			}
 
-		if session.CurrentTimer%5 == 0 || session.CurrentTimer < 5 {
-			fmt.Printf("[TIMER] %s %s: %d sec left (reserve R:%ds / D:%ds)\n",
-				session.Side, session.Stage,
-				session.CurrentTimer,
-				session.ReserveRadiant, session.ReserveDire)
-		}
+		case !now.Before(session.expiresAt()):
+			// резерв закончился — автопик или автобан
+			s.autoAction(session)
+			s.mu.Unlock()
+			continue
 
-		if session.CurrentTimer <= 0 {
-			var reserve *int
-			if session.Side == SideRadiant {
-				reserve = &session.ReserveRadiant
-			} else {
-				reserve = &session.ReserveDire
+		default:
+			session.refresh(now)
+			if session.overtime(now) > 0 && !session.usingReserve {
+				session.usingReserve = true
+				s.publish(session, EventReserveStarted)
 			}
 
-			if *reserve > 0 {
-				*reserve--
-				session.CurrentTimer = 1 // дышим секунду и продолжаем
-			} else {
-				// резерв закончился — автопик или автобан
-				autoHero := randomAvailableHero(session)
-				fmt.Printf("[AUTO] %s auto-%s hero %d (no time left)\n",
-					session.Side, session.Stage, autoHero)
-
-				_ = session.ApplyAction(autoHero)
-				fmt.Printf("[NEXT] Now %s %s (timer %d sec)\n",
-					session.Side, session.Stage, session.CurrentTimer)
+			if session.CurrentTimer%5 == 0 || session.CurrentTimer < 5 {
+				fmt.Printf("[TIMER] %s %s: %d sec left (reserve R:%ds / D:%ds)\n",
+					session.Side, session.Stage,
+					session.CurrentTimer,
+					session.ReserveRadiant, session.ReserveDire)
+			}
+
+			s.publish(session, EventTimer)
+			if ticks++; ticks%persistEvery == 0 {
+				s.persist(session)
+			}
+
+			// Следующий тик — на ближайшей целой секунде до истечения времени
+			wait = session.expiresAt().Sub(now) % time.Second
+			if wait <= 0 {
+				wait = time.Second
 			}
 		}
 
 		s.mu.Unlock()
+		<-s.clock.After(wait)
+	}
+}
+
+// autoAction делает ход за сторону, у которой кончилось время. Героя выбирает
+// не бот сессии, а HeuristicBot: он дешёвый и без состояния, поэтому автоход
+// укладывается в тик таймера (MCTS искал бы до нескольких секунд) и не
+// перетирает план, о котором сообщает бот. Вызывается под s.mu.
+func (s *Store) autoAction(session *DraftSession) {
+	snapshot := session.Clone()
+	autoHero := validHero(session, chooseHero(HeuristicBot{}, &snapshot))
+	fmt.Printf("[AUTO] %s auto-%s hero %d (no time left)\n",
+		session.Side, session.Stage, autoHero)
+
+	if event, err := session.record(autoHero, SourceAuto, ""); err == nil {
+		s.actionApplied(session, EventAutoAction, event)
 	}
 }
 
 // ApplyAction — применяет действие игрока и двигает сессию.
-// Если следующий ход принадлежит боту, запускает ThinkAndAct.
-func (s *Store) ApplyAction(id string, actionType Phase, heroID int) (*DraftSession, error) {
+// token — токен капитана стороны, которая сейчас ходит (если у неё есть капитан).
+// Если следующий ход принадлежит боту, запускает его в фоне.
+func (s *Store) ApplyAction(id, token string, actionType Phase, heroID int) (*DraftSession, error) {
 	if actionType != PhaseBan && actionType != PhasePick {
 		return nil, fmt.Errorf("unsupported action type %q", actionType)
 	}
 
 	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	session, ok := s.sessions[id]
 	if !ok {
-		s.mu.Unlock()
-		return nil, errors.New("session not found")
+		return nil, ErrSessionNotFound
 	}
 
 	if session.Completed {
-		s.mu.Unlock()
 		return nil, errors.New("draft is already completed")
 	}
 
+	if err := session.authorizeTurn(token); err != nil {
+		return nil, err
+	}
+
 	if session.Stage != actionType {
-		s.mu.Unlock()
 		return nil, fmt.Errorf("expected %s action but got %s", session.Stage, actionType)
 	}
 
-	if err := session.ApplyAction(heroID); err != nil {
-		s.mu.Unlock()
+	actor, _ := session.captain(session.Side)
+	event, err := session.record(heroID, SourceHuman, actor.Name)
+	if err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("[ACTION] %s %s hero %d\n", session.Side, session.Stage, heroID)
+	fmt.Printf("[ACTION] %s %s hero %d\n", event.Side, event.Phase, heroID)
+
+	// Рассылаем событие и проверяем, ход ли теперь бота
+	s.actionApplied(session, EventAction, event)
+
+	return s.view(session), nil
+}
+
+// Hover рассылает предварительный выбор героя текущей стороной.
+// Права те же, что у ApplyAction; состояние драфта не меняется.
+func (s *Store) Hover(id, token string, heroID int) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if session.Completed {
+		return errors.New("draft is already completed")
+	}
+	if session.Pause != nil {
+		return errors.New("draft is paused")
+	}
+	if err := session.authorizeTurn(token); err != nil {
+		return err
+	}
+	if heroID <= 0 || !session.IsHeroAvailable(heroID) {
+		return fmt.Errorf("hero %d is not available", heroID)
+	}
+
+	s.events.publish(Event{
+		Type:      EventHover,
+		SessionID: id,
+		State:     s.view(session).State(),
+		Hover:     &HoverInfo{Side: session.Side, Step: session.Step, HeroID: heroID},
+	})
+	return nil
+}
+
+// actionApplied сообщает подписчикам о ходе и, если драфт закончился,
+// о завершении; иначе передаёт ход боту, если сейчас его очередь.
+// Вызывается под s.mu.
+func (s *Store) actionApplied(session *DraftSession, eventType EventType, event DraftEvent) {
+	s.persist(session)
+
+	state := s.view(session).State()
+	s.events.publish(Event{Type: eventType, SessionID: session.ID, State: state, Action: &event})
+
+	if session.Completed {
+		fmt.Printf("[SESSION] Draft %s completed.\n", session.ID)
+		s.events.publish(Event{Type: EventCompleted, SessionID: session.ID, State: state})
+		if len(s.completed) > 0 {
+			clone, handlers := session.Clone(), s.completed
+			go func() {
+				for _, fn := range handlers {
+					fn(clone)
+				}
+			}()
+		}
+		return
+	}
+
 	fmt.Printf("[NEXT] Now %s %s (timer %d sec)\n",
 		session.Side, session.Stage, session.CurrentTimer)
+	s.scheduleBotMove(session)
+}
 
-	nextSide := session.Side
-	nextPhase := session.Stage
-	botSpeed := session.BotSpeed
-	sessionCopy := session.Clone()
-	s.mu.Unlock()
-
-	// Проверяем, ход ли теперь бота
-	if session.BotSide == nextSide {
-		go func() {
-			delay := botThinkDelay(botSpeed)
-			fmt.Printf("[BOT] %s bot (%s) thinking for %v...\n", nextSide, botSpeed, delay)
-			time.Sleep(delay)
-
-			s.mu.Lock()
-			defer s.mu.Unlock()
-
-			sess, ok := s.sessions[id]
-			if !ok || sess.Completed {
-				return
-			}
+// view — копия сессии с оставшимся временем на текущий момент часов Store.
+// Вызывается под s.mu (на чтение или запись).
+func (s *Store) view(session *DraftSession) *DraftSession {
+	clone := session.Clone()
+	clone.refresh(s.clock.Now())
+	return &clone
+}
 
-			hero := randomAvailableHero(sess)
-			_ = sess.ApplyAction(hero)
-			fmt.Printf("[BOT] %s auto-%s hero %d after %v\n", nextSide, nextPhase, hero, delay)
-			fmt.Printf("[NEXT] Now %s %s (timer %d sec)\n",
-				sess.Side, sess.Stage, sess.CurrentTimer)
-		}()
-	}
+// persist снимает запись с сессии и отдаёт её фоновой записи в репозиторий,
+// не дожидаясь диска. Вызывается под s.mu.
+func (s *Store) persist(session *DraftSession) {
+	s.writer.enqueue(newRecord(session, s.clock.Now()))
+}
 
-	return &sessionCopy, nil
+// Flush ждёт, пока все снимки сессий, сохранённые до вызова, не будут
+// записаны в репозиторий. Вызывайте перед закрытием репозитория.
+func (s *Store) Flush() {
+	s.writer.flush()
 }
 
-func randomAvailableHero(s *DraftSession) int {
-	for _, h := range heroes.All() {
-		if !s.IsHeroUsed(h.ID) {
-			return h.ID
+// Restore загружает из репозитория незавершённые сессии, пересоздаёт их
+// ботов и возобновляет таймеры. Возвращает число восстановленных сессий.
+func (s *Store) Restore() (int, error) {
+	records, err := s.repo.List()
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	restored := 0
+	for _, record := range records {
+		if record.Session.Completed {
+			continue
+		}
+		if _, ok := s.sessions[record.Session.ID]; ok {
+			continue
+		}
+
+		session := record.restore()
+		factory, ok := s.bots[session.BotType]
+		if !ok {
+			fmt.Printf("[STORE] bot %q of session %s is not registered, using %s\n",
+				session.BotType, session.ID, DefaultBotType)
+			session.BotType = DefaultBotType
+			factory = s.bots[DefaultBotType]
+		}
+		session.clock = s.clock
+		session.catalog = s.catalog
+		if err := session.pinPatch(); err != nil {
+			fmt.Printf("[STORE] session %s is not restored: %v\n", session.ID, err)
+			continue
 		}
+		session.bot = factory()
+
+		// Пока сервис стоял, время хода не шло: сдвигаем дедлайны на простой
+		session.shift(now.Sub(record.SavedAt))
+		session.refresh(now)
+
+		s.sessions[session.ID] = session
+		s.startTimer(session)
+		s.scheduleBotMove(session)
+		restored++
+
+		fmt.Printf("[STORE] Restored draft %s at step %d (%s %s, timer %d sec)\n",
+			session.ID, session.Step, session.Side, session.Stage, session.CurrentTimer)
+	}
+	return restored, nil
+}
+
+// publish рассылает подписчикам событие без хода. Вызывается под s.mu.
+func (s *Store) publish(session *DraftSession, eventType EventType) {
+	s.events.publish(Event{Type: eventType, SessionID: session.ID, State: s.view(session).State()})
+}
+
+// Subscribe подписывает на события сессии от имени участника лобби с token
+// (пустой — аноним, которому не приходят подсказки сторон). Возвращает
+// текущее состояние, канал событий и функцию отписки, которую нужно вызвать
+// по завершении.
+func (s *Store) Subscribe(id, token string) (SessionState, <-chan Event, func(), error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return SessionState{}, nil, nil, ErrSessionNotFound
+	}
+
+	// Подписываемся под блокировкой, чтобы не пропустить события между
+	// снимком состояния и подпиской.
+	ch, unsubscribe := s.events.subscribe(id, token)
+	return s.view(session).State(), ch, unsubscribe, nil
+}
+
+// scheduleBotMove запускает ход бота, если сейчас очередь его стороны.
+// Вызывается под s.mu.
+func (s *Store) scheduleBotMove(session *DraftSession) {
+	if session.Completed || session.Pause != nil || session.bot == nil || session.Side != session.BotSide {
+		return
+	}
+	go s.botMove(session, session.Step)
+}
+
+// botMove — «думает» в течение задержки, выбирает героя и делает ход,
+// если за это время ход step ещё не был сделан (например, автопиком).
+func (s *Store) botMove(session *DraftSession, step int) {
+	delay := botThinkDelay(session.BotSpeed)
+
+	s.mu.RLock()
+	revision := session.revision
+	speed := session.BotSpeed
+	side := session.Side
+	phase := session.Stage
+	snapshot := session.Clone()
+	bot := session.bot
+	s.events.publish(Event{
+		Type:      EventBotThinking,
+		SessionID: session.ID,
+		State:     s.view(session).State(),
+		Bot:       &BotThinking{Side: side, Step: step, DelayMs: delay.Milliseconds(), BotSpeed: speed},
+	})
+	s.mu.RUnlock()
+
+	fmt.Printf("[BOT] %s bot (%s) thinking for %v...\n", side, speed, delay)
+
+	started := s.clock.Now()
+	hero := chooseHero(bot, &snapshot)
+	if rest := delay - s.clock.Now().Sub(started); rest > 0 {
+		<-s.clock.After(rest)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session.Completed || session.Pause != nil || session.Step != step || session.revision != revision {
+		return
+	}
+
+	hero = validHero(session, hero)
+	event, err := session.record(hero, SourceBot, "")
+	if err != nil {
+		fmt.Printf("[BOT] %s failed to %s hero %d: %v\n", side, phase, hero, err)
+		return
+	}
+	if reporter, ok := bot.(PlanReporter); ok {
+		session.BotPlan = reporter.PrincipalVariation()
+		fmt.Printf("[BOT] %s plan: %v\n", side, session.BotPlan)
+	}
+	fmt.Printf("[BOT] %s %s hero %d after %v\n", side, phase, hero, delay)
+
+	// У бота может быть несколько ходов подряд (например, двойной бан).
+	s.actionApplied(session, EventAction, event)
+}
+
+// chooseHero спрашивает бота о ходе. Бот получает клон сессии и работает
+// без блокировки Store, поэтому может думать сколько угодно.
+func chooseHero(bot Bot, snapshot *DraftSession) int {
+	if bot == nil {
+		return 0
+	}
+	return bot.ChooseHero(snapshot)
+}
+
+// validHero возвращает heroID, если он ещё свободен, иначе первого
+// свободного героя — на случай, если бот ошибся или ничего не нашёл.
+func validHero(s *DraftSession, heroID int) int {
+	if heroID > 0 && s.IsHeroAvailable(heroID) {
+		return heroID
+	}
+	return firstAvailableHero(s)
+}
+
+func firstAvailableHero(s *DraftSession) int {
+	if available := s.AvailableHeroes(); len(available) > 0 {
+		return available[0].ID
 	}
 	return 1 // fallback
 }
@@ -224,10 +619,20 @@ func (s *Store) GetSession(id string) (*DraftSession, error) {
 
 	session, ok := s.sessions[id]
 	if !ok {
-		return nil, errors.New("session not found")
+		// Завершённые до рестарта драфты есть только в репозитории.
+		record, err := s.repo.Load(id)
+		if err != nil {
+			return nil, ErrSessionNotFound
+		}
+		session := record.restore()
+		session.catalog = s.catalog
+		if err := session.pinPatch(); err != nil {
+			// Завершённый драфт только показываем: без правил патча он читается так же
+			fmt.Printf("[STORE] session %s: %v\n", id, err)
+		}
+		return session, nil
 	}
-	clone := session.Clone()
-	return &clone, nil
+	return s.view(session), nil
 }
 
 // ClonePtr — удобный способ вернуть указатель на клон.
@@ -0,0 +1,213 @@
+package draft
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// timerSession — сессия двух команд людей без бота: время тратит только
+// runTimer на FakeClock.
+func timerSession(t *testing.T) (*Store, *FakeClock, string) {
+	t.Helper()
+	clock := NewFakeClock(time.Unix(0, 0))
+	store := NewStoreWithConfig(StoreConfig{Clock: clock})
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return store, clock, session.ID
+}
+
+// runFor двигает часы на d, останавливаясь на каждом пробуждении runTimer,
+// чтобы тот успел разослать тики и сделать автоход вовремя.
+func runFor(clock *FakeClock, d time.Duration) {
+	end := clock.Now().Add(d)
+	for {
+		clock.BlockUntil(1)
+		now := clock.Now()
+		if !now.Before(end) {
+			return
+		}
+		clock.mu.Lock()
+		next := end
+		for _, w := range clock.waiters {
+			if w.at.Before(next) {
+				next = w.at
+			}
+		}
+		clock.mu.Unlock()
+		clock.Advance(next.Sub(now))
+	}
+}
+
+func getSession(t *testing.T, store *Store, id string) *DraftSession {
+	t.Helper()
+	session, err := store.GetSession(id)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	return session
+}
+
+func TestRunTimerAutoPicksWhenTimerAndReserveRunOut(t *testing.T) {
+	store, clock, id := timerSession(t)
+	session := getSession(t, store, id)
+	timer := time.Duration(session.Order[0].Timer) * time.Second
+	reserve := time.Duration(session.ReserveRadiant) * time.Second
+
+	runFor(clock, timer+reserve-time.Millisecond)
+	session = getSession(t, store, id)
+	if len(session.Events) != 0 {
+		t.Fatalf("auto action before the reserve ran out: %+v", session.Events)
+	}
+	if session.TimerMs != 0 || session.ReserveRadiantMs != 1 {
+		t.Fatalf("before expiry: timer %d ms, reserve %d ms, want 0 and 1",
+			session.TimerMs, session.ReserveRadiantMs)
+	}
+
+	runFor(clock, time.Millisecond)
+	session = getSession(t, store, id)
+	if len(session.Events) != 1 {
+		t.Fatalf("expected one auto action, got %+v", session.Events)
+	}
+	event := session.Events[0]
+	if event.Source != SourceAuto || event.Side != SideRadiant || event.HeroID <= 0 {
+		t.Fatalf("unexpected auto action: %+v", event)
+	}
+	if event.ReserveMs != 0 || event.ReserveUsed != int(reserve/time.Second) {
+		t.Fatalf("auto action reserve: %d ms left, %d sec used", event.ReserveMs, event.ReserveUsed)
+	}
+	if session.Side != SideDire || session.ReserveRadiant != 0 || session.CurrentTimer != session.Order[1].Timer {
+		t.Fatalf("next turn: %s, radiant reserve %d, timer %d",
+			session.Side, session.ReserveRadiant, session.CurrentTimer)
+	}
+}
+
+func TestRunTimerCarriesPartialReserve(t *testing.T) {
+	store, clock, id := timerSession(t)
+	session := getSession(t, store, id)
+	timer := time.Duration(session.Order[0].Timer) * time.Second
+	reserve := time.Duration(session.ReserveRadiant) * time.Second
+	spent := 20500 * time.Millisecond
+
+	// Radiant уходит в резерв на 20.5 секунды и только потом банит.
+	runFor(clock, timer+spent)
+	if err := act(store, getSession(t, store, id), ""); err != nil {
+		t.Fatalf("radiant action: %v", err)
+	}
+	session = getSession(t, store, id)
+	left := reserve - spent
+	if session.ReserveRadiantMs != left.Milliseconds() || session.ReserveRadiant != 110 {
+		t.Fatalf("radiant reserve after the turn: %d ms (%d sec), want %d ms (110 sec)",
+			session.ReserveRadiantMs, session.ReserveRadiant, left.Milliseconds())
+	}
+	if got := session.Events[0].ReserveUsed; got != 20 {
+		t.Fatalf("ReserveUsed = %d, want 20", got)
+	}
+
+	// Dire отвечает сразу, и следующий ход Radiant тратит уже остаток резерва.
+	if err := act(store, session, ""); err != nil {
+		t.Fatalf("dire action: %v", err)
+	}
+	timer = time.Duration(session.Order[2].Timer) * time.Second
+	runFor(clock, timer+left-time.Millisecond)
+	if session = getSession(t, store, id); len(session.Events) != 2 {
+		t.Fatalf("auto action before the carried reserve ran out: %+v", session.Events)
+	}
+
+	runFor(clock, time.Millisecond)
+	session = getSession(t, store, id)
+	if len(session.Events) != 3 || session.Events[2].Source != SourceAuto || session.Events[2].Side != SideRadiant {
+		t.Fatalf("expected a radiant auto action on step 2, got %+v", session.Events)
+	}
+	if session.ReserveRadiantMs != 0 {
+		t.Fatalf("radiant reserve after auto action: %d ms, want 0", session.ReserveRadiantMs)
+	}
+}
+
+func TestRunTimerSubSecondValues(t *testing.T) {
+	store, clock, id := timerSession(t)
+	session := getSession(t, store, id)
+	timer := time.Duration(session.Order[0].Timer) * time.Second
+	reserve := time.Duration(session.ReserveRadiant) * time.Second
+
+	runFor(clock, timer-250*time.Millisecond)
+	session = getSession(t, store, id)
+	if session.TimerMs != 250 || session.CurrentTimer != 1 {
+		t.Fatalf("timer: %d ms (%d sec), want 250 ms (1 sec)", session.TimerMs, session.CurrentTimer)
+	}
+
+	runFor(clock, 500*time.Millisecond)
+	session = getSession(t, store, id)
+	wantReserve := (reserve - 250*time.Millisecond).Milliseconds()
+	if session.TimerMs != 0 || session.CurrentTimer != 0 {
+		t.Fatalf("timer after expiry: %d ms (%d sec), want 0", session.TimerMs, session.CurrentTimer)
+	}
+	if session.ReserveRadiantMs != wantReserve || session.ReserveRadiant != int(reserve/time.Second) {
+		t.Fatalf("reserve: %d ms (%d sec), want %d ms (rounded up to %d sec)",
+			session.ReserveRadiantMs, session.ReserveRadiant, wantReserve, reserve/time.Second)
+	}
+
+	if err := act(store, session, ""); err != nil {
+		t.Fatalf("radiant action: %v", err)
+	}
+	event := getSession(t, store, id).Events[0]
+	if event.TimerMs != 0 || event.ReserveMs != wantReserve || event.ReserveUsed != 0 {
+		t.Fatalf("action timing: timer %d ms, reserve %d ms, used %d sec",
+			event.TimerMs, event.ReserveMs, event.ReserveUsed)
+	}
+}
+
+// spyBot запоминает, за какие стороны его спрашивали о ходе.
+type spyBot struct {
+	mu    *sync.Mutex
+	sides *[]Side
+}
+
+func (b spyBot) ChooseHero(session *DraftSession) int {
+	b.mu.Lock()
+	*b.sides = append(*b.sides, session.Side)
+	b.mu.Unlock()
+	return firstAvailableHero(session)
+}
+
+func TestAutoActionDoesNotAskSessionBot(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	store := NewStoreWithConfig(StoreConfig{Clock: clock})
+	var mu sync.Mutex
+	var sides []Side
+	store.RegisterBot("spy", func() Bot { return spyBot{mu: &mu, sides: &sides} })
+	session, err := store.CreateSession(context.Background(), SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   SideRadiant,
+		BotSide:     SideDire,
+		BotType:     "spy",
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	expiry := time.Duration(session.Order[0].Timer)*time.Second +
+		time.Duration(session.ReserveRadiant)*time.Second
+
+	runFor(clock, expiry)
+	session = getSession(t, store, session.ID)
+	if len(session.Events) == 0 || session.Events[0].Source != SourceAuto {
+		t.Fatalf("expected a radiant auto action, got %+v", session.Events)
+	}
+	// Бот Dire после автопика вправе сходить сам — важно лишь, что за
+	// Radiant его не спрашивали.
+	mu.Lock()
+	defer mu.Unlock()
+	for _, side := range sides {
+		if side != SideDire {
+			t.Fatalf("auto action asked the session bot to move for %s", side)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package draft
+
+import "time"
+
+// ReserveBank — резерв сторон на начало текущего хода. Резерв стороны,
+// которая ходит, расходуется по мере того, как время уходит за TurnDeadline.
+type ReserveBank struct {
+	Radiant time.Duration `json:"radiant"`
+	Dire    time.Duration `json:"dire"`
+}
+
+// now — время по часам сессии (системное, если часы не заданы).
+func (s *DraftSession) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+// bank возвращает указатель на резерв стороны на начало хода.
+func (s *DraftSession) bank(side Side) *time.Duration {
+	if side == SideRadiant {
+		return &s.ReserveBank.Radiant
+	}
+	return &s.ReserveBank.Dire
+}
+
+// reserve возвращает указатель на оставшийся резерв стороны в секундах
+// (значение на момент последнего refresh).
+func (s *DraftSession) reserve(side Side) *int {
+	if side == SideRadiant {
+		return &s.ReserveRadiant
+	}
+	return &s.ReserveDire
+}
+
+// startTurn запускает основной таймер текущего хода с момента now.
+func (s *DraftSession) startTurn(now time.Time) {
+	s.usingReserve = false
+	s.TurnDeadline = now.Add(time.Duration(s.Order[s.Step].Timer) * time.Second)
+	s.refresh(now)
+}
+
+// overtime — сколько времени текущий ход идёт сверх основного таймера.
+// На паузе время остановлено на её начале.
+func (s *DraftSession) overtime(now time.Time) time.Duration {
+	if s.Pause != nil {
+		now = s.Pause.Since
+	}
+	return max(now.Sub(s.TurnDeadline), 0)
+}
+
+// expiresAt — когда у текущей стороны кончатся таймер и резерв.
+func (s *DraftSession) expiresAt() time.Time {
+	return s.TurnDeadline.Add(*s.bank(s.Side))
+}
+
+// spendReserve списывает с банка стороны резерв, потраченный на текущий ход.
+func (s *DraftSession) spendReserve(now time.Time) {
+	bank := s.bank(s.Side)
+	*bank -= min(s.overtime(now), *bank)
+}
+
+// refresh пересчитывает оставшееся время на момент now: основной таймер,
+// резерв сторон (с учётом перерасхода текущей) и их значения в миллисекундах.
+func (s *DraftSession) refresh(now time.Time) {
+	if s.Pause != nil {
+		now = s.Pause.Since
+	}
+
+	left := time.Duration(0)
+	radiant, dire := s.ReserveBank.Radiant, s.ReserveBank.Dire
+	if !s.Completed {
+		left = max(s.TurnDeadline.Sub(now), 0)
+		spent := s.overtime(now)
+		if s.Side == SideRadiant {
+			radiant = max(radiant-spent, 0)
+		} else {
+			dire = max(dire-spent, 0)
+		}
+	}
+
+	s.CurrentTimer, s.TimerMs = ceilSeconds(left), left.Milliseconds()
+	s.ReserveRadiant, s.ReserveRadiantMs = ceilSeconds(radiant), radiant.Milliseconds()
+	s.ReserveDire, s.ReserveDireMs = ceilSeconds(dire), dire.Milliseconds()
+}
+
+// shift сдвигает дедлайны сессии на d — время, которое она простояла
+// (пауза, перезапуск сервиса).
+func (s *DraftSession) shift(d time.Duration) {
+	s.TurnDeadline = s.TurnDeadline.Add(d)
+	if s.Pause != nil {
+		s.Pause.Since = s.Pause.Since.Add(d)
+	}
+}
+
+// ceilSeconds округляет длительность вверх до целых секунд.
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
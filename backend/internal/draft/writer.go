@@ -0,0 +1,83 @@
+package draft
+
+import (
+	"fmt"
+	"sync"
+)
+
+// sessionWriter сохраняет снимки сессий в репозиторий из отдельной горутины,
+// чтобы запись на диск (bbolt делает fsync на каждую транзакцию) не шла
+// под s.mu и не задерживала таймеры и ходы других сессий. Снимок снимается
+// под s.mu, а в очереди остаётся только последний снимок каждой сессии:
+// промежуточные всё равно были бы перезаписаны.
+type sessionWriter struct {
+	repo SessionRepository
+
+	mu      sync.Mutex
+	pending map[string]SessionRecord
+	order   []string
+	writing bool
+	// Сигнал Flush о том, что очередь опустела
+	idle *sync.Cond
+	wake chan struct{}
+}
+
+func newSessionWriter(repo SessionRepository) *sessionWriter {
+	w := &sessionWriter{
+		repo:    repo,
+		pending: make(map[string]SessionRecord),
+		wake:    make(chan struct{}, 1),
+	}
+	w.idle = sync.NewCond(&w.mu)
+	go w.run()
+	return w
+}
+
+// enqueue ставит снимок в очередь, заменяя ещё не записанный снимок той же сессии.
+func (w *sessionWriter) enqueue(record SessionRecord) {
+	w.mu.Lock()
+	id := record.Session.ID
+	if _, queued := w.pending[id]; !queued {
+		w.order = append(w.order, id)
+	}
+	w.pending[id] = record
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// flush ждёт, пока все поставленные в очередь снимки не будут записаны.
+func (w *sessionWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(w.order) > 0 || w.writing {
+		w.idle.Wait()
+	}
+}
+
+func (w *sessionWriter) run() {
+	for range w.wake {
+		for {
+			w.mu.Lock()
+			if len(w.order) == 0 {
+				w.writing = false
+				w.idle.Broadcast()
+				w.mu.Unlock()
+				break
+			}
+			batch, order := w.pending, w.order
+			w.pending, w.order = make(map[string]SessionRecord), nil
+			w.writing = true
+			w.mu.Unlock()
+
+			for _, id := range order {
+				if err := w.repo.Save(batch[id]); err != nil {
+					fmt.Printf("[STORE] failed to save session %s: %v\n", id, err)
+				}
+			}
+		}
+	}
+}
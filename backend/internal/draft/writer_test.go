@@ -0,0 +1,67 @@
+package draft
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// blockingRepository — репозиторий, запись в который ждёт release, как
+// медленный fsync.
+type blockingRepository struct {
+	*MemoryRepository
+	release chan struct{}
+}
+
+func (r blockingRepository) Save(record SessionRecord) error {
+	<-r.release
+	return r.MemoryRepository.Save(record)
+}
+
+func TestPersistDoesNotWaitForRepository(t *testing.T) {
+	repo := blockingRepository{MemoryRepository: NewMemoryRepository(), release: make(chan struct{})}
+	store := NewStoreWithConfig(StoreConfig{Repository: repo, Clock: NewFakeClock(time.Unix(0, 0))})
+
+	done := make(chan struct{})
+	var session *DraftSession
+	go func() {
+		defer close(done)
+		var err error
+		session, err = store.CreateSession(context.Background(), SessionConfig{
+			RadiantName: "Liquid",
+			DireName:    "Spirit",
+			FirstPick:   SideRadiant,
+		})
+		if err != nil {
+			t.Errorf("create session: %v", err)
+			return
+		}
+		if _, err := store.Join(session.ID, "Miracle", LobbyCaptain, SideRadiant, ""); err != nil {
+			t.Errorf("join: %v", err)
+		}
+		// Чтение сессии не ждёт записи на диск.
+		if _, err := store.GetSession(session.ID); err != nil {
+			t.Errorf("get session: %v", err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("store operations blocked on a slow repository")
+	}
+	if t.Failed() {
+		return
+	}
+
+	close(repo.release)
+	store.Flush()
+
+	record, err := repo.Load(session.ID)
+	if err != nil {
+		t.Fatalf("session was not saved: %v", err)
+	}
+	if len(record.Session.Lobby) != 1 || len(record.Tokens) != 1 {
+		t.Fatalf("saved record is not the latest snapshot: lobby=%+v", record.Session.Lobby)
+	}
+}
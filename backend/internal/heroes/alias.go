@@ -0,0 +1,111 @@
+package heroes
+
+// aliases maps community nicknames to hero ids. Keys are normalized (see
+// normalize): lower case, letters and digits only. Full hero names do not
+// need an entry, they are matched by the search itself.
+var aliases = map[string]int{
+	"am":      1,
+	"magina":  1,
+	"bs":      4,
+	"cm":      5,
+	"rylai":   5,
+	"drow":    6,
+	"es":      7,
+	"shaker":  7,
+	"jugg":    8,
+	"potm":    9,
+	"morph":   10,
+	"sf":      11,
+	"pl":      12,
+	"sk":      16,
+	"storm":   17,
+	"vs":      20,
+	"venge":   20,
+	"wr":      21,
+	"wind":    21,
+	"zuus":    22,
+	"coco":    23,
+	"rhasta":  27,
+	"ss":      27,
+	"shaman":  27,
+	"tide":    29,
+	"wd":      30,
+	"necro":   36,
+	"lock":    37,
+	"bm":      38,
+	"qop":     39,
+	"veno":    40,
+	"fv":      41,
+	"void":    41,
+	"wk":      42,
+	"dp":      43,
+	"pa":      44,
+	"ta":      46,
+	"dk":      49,
+	"clock":   51,
+	"cw":      51,
+	"lesh":    52,
+	"np":      53,
+	"furion":  53,
+	"ls":      54,
+	"naix":    54,
+	"ds":      55,
+	"omni":    57,
+	"ench":    58,
+	"ns":      60,
+	"brood":   61,
+	"bh":      62,
+	"gondar":  62,
+	"jak":     64,
+	"bat":     65,
+	"aa":      68,
+	"sb":      71,
+	"bara":    71,
+	"gyro":    72,
+	"alch":    73,
+	"invo":    74,
+	"voker":   74,
+	"sil":     75,
+	"od":      76,
+	"brew":    78,
+	"sd":      79,
+	"ld":      80,
+	"ck":      81,
+	"tree":    83,
+	"ogre":    84,
+	"dirge":   85,
+	"nyx":     88,
+	"naga":    89,
+	"kotl":    90,
+	"wisp":    91,
+	"dusa":    94,
+	"troll":   95,
+	"cent":    96,
+	"mag":     97,
+	"timber":  98,
+	"bb":      99,
+	"sky":     101,
+	"aba":     102,
+	"et":      103,
+	"lc":      104,
+	"ember":   106,
+	"kaolin":  107,
+	"ul":      108,
+	"pitlord": 108,
+	"tb":      109,
+	"ww":      112,
+	"wyvern":  112,
+	"arc":     113,
+	"zet":     113,
+	"mk":      114,
+	"dw":      119,
+	"willow":  119,
+	"pango":   120,
+	"grim":    121,
+	"hood":    123,
+	"snap":    128,
+	"rm":      131,
+	"db":      135,
+	"dawn":    135,
+	"pb":      137,
+}
@@ -0,0 +1,75 @@
+package heroes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAliasesNameCatalogHeroes(t *testing.T) {
+	catalog := SnapshotCatalog()
+	for alias, id := range aliases {
+		if alias != normalize(alias) {
+			t.Errorf("alias %q is not normalized", alias)
+		}
+		if _, ok := catalog.Get(id); !ok {
+			t.Errorf("alias %q points at unknown hero %d", alias, id)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	catalog := SnapshotCatalog()
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"am", 1},
+		{"AM", 1},
+		{"Q.o.P", 39},
+		{"Nature's Prophet", 53},
+		{"natures prophet", 53},
+		{"furion", 53},
+		{"jugger", 8},
+		{"invokr", 74},
+		// The alias wins over prefixes of other names
+		{"storm", 17},
+	}
+	for _, tt := range tests {
+		hero, err := catalog.Resolve(tt.name)
+		if err != nil {
+			t.Errorf("Resolve(%q): %v", tt.name, err)
+			continue
+		}
+		if hero.ID != tt.want {
+			t.Errorf("Resolve(%q) = %d (%s), want %d", tt.name, hero.ID, hero.LocalizedName, tt.want)
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	catalog := SnapshotCatalog()
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"", []string{"empty hero name"}},
+		{"  ?! ", []string{"empty hero name"}},
+		{"zzzzzz", []string{"no hero matches"}},
+		{"shadow", []string{"ambiguous", "Shadow Fiend", "Shadow Shaman", "Shadow Demon"}},
+		{"prophet", []string{"ambiguous", "Death Prophet", "Nature's Prophet"}},
+	}
+	for _, tt := range tests {
+		hero, err := catalog.Resolve(tt.name)
+		if err == nil {
+			t.Errorf("Resolve(%q) = %s, want an error", tt.name, hero.LocalizedName)
+			continue
+		}
+		for _, part := range tt.want {
+			if !strings.Contains(err.Error(), part) {
+				t.Errorf("Resolve(%q) error %q does not mention %q", tt.name, err, part)
+			}
+		}
+	}
+}
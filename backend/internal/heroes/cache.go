@@ -1,91 +1,89 @@
 package heroes
 
 import (
+	"bytes"
 	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"sync"
+	"io"
+	"os"
+	"path/filepath"
 	"time"
 )
 
-const heroStatsURL = "https://api.opendota.com/api/heroStats"
-const refreshInterval = 24 * time.Hour
-
-var (
-	cacheMu sync.RWMutex
-	cache   []Hero
-	client  = &http.Client{Timeout: 10 * time.Second}
-)
-
-// Init loads the hero catalog and starts a background refresher that keeps the data
-// in sync with OpenDota. It must be called before accessing the hero cache.
-func Init() error {
-	heroes, err := fetch()
-	if err != nil {
-		return err
-	}
-
-	cacheMu.Lock()
-	cache = heroes
-	cacheMu.Unlock()
+// cacheFile is the on-disk form of a catalog: the hero list together with
+// where and when it was fetched. The embedded snapshot uses the same shape.
+type cacheFile struct {
+	Source    string    `json:"source"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Heroes    []Hero    `json:"heroes"`
+}
 
-	go refresher()
+// CacheProvider serves the catalog last written to a cache file by
+// Catalog.WithCache. It reports the time of the original fetch.
+type CacheProvider struct {
+	path string
+}
 
-	return nil
+// NewCacheProvider returns a provider for the cache file at path.
+func NewCacheProvider(path string) *CacheProvider {
+	return &CacheProvider{path: path}
 }
 
-// All returns a copy of the cached hero slice.
-func All() []Hero {
-	cacheMu.RLock()
-	defer cacheMu.RUnlock()
+func (p *CacheProvider) Name() string { return "cache" }
 
-	result := make([]Hero, len(cache))
-	copy(result, cache)
-	return result
+func (p *CacheProvider) Heroes() ([]Hero, error) {
+	heroes, _, err := p.fetchDated()
+	return heroes, err
 }
 
-func refresher() {
-	ticker := time.NewTicker(refreshInterval)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		heroes, err := fetch()
-		if err != nil {
-			log.Printf("heroes: refresher failed to pull hero stats: %v", err)
-			continue
-		}
+func (p *CacheProvider) fetchDated() ([]Hero, time.Time, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+	return readCache(f)
+}
 
-		cacheMu.Lock()
-		cache = heroes
-		cacheMu.Unlock()
+func readCache(r io.Reader) ([]Hero, time.Time, error) {
+	var file cacheFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, time.Time{}, err
 	}
+	return file.Heroes, file.FetchedAt, nil
 }
 
-func fetch() ([]Hero, error) {
-	req, err := http.NewRequest(http.MethodGet, heroStatsURL, nil)
+// writeCache atomically replaces the cache file: the catalog is written to a
+// temporary file next to it and renamed over, so readers never see a torn file.
+func writeCache(path string, file cacheFile) error {
+	data, err := json.Marshal(file)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	resp, err := client.Do(req)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
+	defer os.Remove(tmp.Name())
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected response from hero stats API: %d", resp.StatusCode)
+	if _, err := io.Copy(tmp, bytes.NewReader(data)); err != nil {
+		tmp.Close()
+		return err
 	}
-
-	var heroes []Hero
-	if err := json.NewDecoder(resp.Body).Decode(&heroes); err != nil {
-		return nil, err
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
 	}
-
-	if len(heroes) == 0 {
-		return nil, fmt.Errorf("hero stats API returned no heroes")
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
 	}
-
-	return heroes, nil
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
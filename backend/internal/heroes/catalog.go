@@ -0,0 +1,193 @@
+package heroes
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const refreshInterval = 24 * time.Hour
+
+// Bounds of the exponential backoff between provider retries after a failed
+// startup load.
+const (
+	retryMinInterval = 5 * time.Second
+	retryMaxInterval = 10 * time.Minute
+)
+
+// Catalog is the hero list loaded from a Provider. It is safe for concurrent
+// use; a failed reload keeps the previously loaded heroes.
+type Catalog struct {
+	provider Provider
+	// Patch table, oldest first
+	patches []Patch
+	// Every successful load from a live provider is written here
+	cachePath string
+	// Waits between provider retries; time.Sleep outside tests
+	sleep func(time.Duration)
+
+	mu        sync.RWMutex
+	heroes    []Hero
+	source    string
+	fetchedAt time.Time
+}
+
+// NewCatalog returns an empty catalog backed by provider. Call Load to fill it.
+func NewCatalog(provider Provider) *Catalog {
+	return &Catalog{provider: provider, patches: patches, sleep: time.Sleep}
+}
+
+// SnapshotCatalog returns a catalog loaded from the snapshot embedded in the
+// binary. It panics only if the binary was built with a broken snapshot.
+func SnapshotCatalog() *Catalog {
+	catalog := NewCatalog(NewSnapshotProvider())
+	if err := catalog.Load(); err != nil {
+		panic(fmt.Sprintf("heroes: embedded snapshot is broken: %v", err))
+	}
+	return catalog
+}
+
+// WithCache makes the catalog write every successful load from its provider
+// to the cache file at path. LoadOrFallback reads the file back when the
+// provider is unreachable.
+func (c *Catalog) WithCache(path string) *Catalog {
+	c.cachePath = path
+	return c
+}
+
+// Load pulls heroes from the provider and replaces the catalog contents.
+func (c *Catalog) Load() error {
+	if err := c.loadFrom(c.provider); err != nil {
+		return err
+	}
+	if _, dated := c.provider.(datedProvider); dated || c.cachePath == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	file := cacheFile{Source: c.source, FetchedAt: c.fetchedAt, Heroes: c.heroes}
+	c.mu.RUnlock()
+	if err := writeCache(c.cachePath, file); err != nil {
+		log.Printf("heroes: failed to write cache %s: %v", c.cachePath, err)
+	}
+	return nil
+}
+
+// LoadOrFallback loads the catalog from its provider. When the provider fails
+// it serves the cache file, or the embedded snapshot if there is no usable
+// cache, and keeps retrying the provider in the background with exponential
+// backoff until it answers.
+func (c *Catalog) LoadOrFallback() error {
+	err := c.Load()
+	if err == nil {
+		return nil
+	}
+	log.Printf("heroes: %v", err)
+
+	fallbacks := []Provider{NewSnapshotProvider()}
+	if c.cachePath != "" {
+		fallbacks = append([]Provider{NewCacheProvider(c.cachePath)}, fallbacks...)
+	}
+	for _, fallback := range fallbacks {
+		if fallbackErr := c.loadFrom(fallback); fallbackErr != nil {
+			log.Printf("heroes: %v", fallbackErr)
+			continue
+		}
+		log.Printf("heroes: serving %d heroes from %s fetched at %s, retrying %s in the background",
+			len(c.All()), fallback.Name(), c.FetchedAt().Format(time.RFC3339), c.provider.Name())
+		go c.retry()
+		return nil
+	}
+	return err
+}
+
+// retry reloads the catalog from the provider until it succeeds, doubling
+// the pause after every failure.
+func (c *Catalog) retry() {
+	wait := retryMinInterval
+	for {
+		c.sleep(wait)
+		err := c.Load()
+		if err == nil {
+			log.Printf("heroes: loaded %d heroes from %s", len(c.All()), c.provider.Name())
+			return
+		}
+		wait = min(2*wait, retryMaxInterval)
+		log.Printf("heroes: retry failed, next attempt in %s: %v", wait, err)
+	}
+}
+
+func (c *Catalog) loadFrom(provider Provider) error {
+	heroes, fetchedAt, err := fetch(provider)
+	if err != nil {
+		return fmt.Errorf("load heroes from %s: %w", provider.Name(), err)
+	}
+	if len(heroes) == 0 {
+		return fmt.Errorf("load heroes from %s: no heroes", provider.Name())
+	}
+
+	c.mu.Lock()
+	c.heroes = heroes
+	c.source = provider.Name()
+	c.fetchedAt = fetchedAt
+	c.mu.Unlock()
+	return nil
+}
+
+// StartRefresher reloads the catalog every interval (24h if zero) in the
+// background for the lifetime of the process.
+func (c *Catalog) StartRefresher(interval time.Duration) {
+	if interval <= 0 {
+		interval = refreshInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := c.Load(); err != nil {
+				log.Printf("heroes: refresher failed: %v", err)
+			}
+		}
+	}()
+}
+
+// Source names the provider the current heroes came from: the catalog
+// provider, "cache" or "snapshot".
+func (c *Catalog) Source() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.source
+}
+
+// FetchedAt returns when the current heroes were fetched from their origin;
+// for the cache and the snapshot this is the time of the original fetch.
+func (c *Catalog) FetchedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fetchedAt
+}
+
+// All returns a copy of the catalog.
+func (c *Catalog) All() []Hero {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]Hero, len(c.heroes))
+	copy(result, c.heroes)
+	return result
+}
+
+// Get looks a hero up by id.
+func (c *Catalog) Get(id int) (Hero, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, h := range c.heroes {
+		if h.ID == id {
+			return h, true
+		}
+	}
+	return Hero{}, false
+}
@@ -0,0 +1,184 @@
+package heroes
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyProvider fails its first failures calls and then serves heroes.
+type flakyProvider struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	heroes   []Hero
+}
+
+func (p *flakyProvider) Name() string { return "flaky" }
+
+func (p *flakyProvider) Heroes() ([]Hero, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, errors.New("provider is unreachable")
+	}
+	return p.heroes, nil
+}
+
+var liveHeroes = []Hero{
+	{ID: 1, Name: "npc_dota_hero_antimage", LocalizedName: "Anti-Mage"},
+	{ID: 2, Name: "npc_dota_hero_axe", LocalizedName: "Axe"},
+	{ID: 3, Name: "npc_dota_hero_bane", LocalizedName: "Bane"},
+}
+
+// fallbackCatalog returns a catalog whose provider fails once and whose
+// background retry blocks until the test receives from the returned channel.
+func fallbackCatalog(cachePath string) (*Catalog, chan time.Duration) {
+	waits := make(chan time.Duration)
+	catalog := NewCatalog(&flakyProvider{failures: 1, heroes: liveHeroes}).WithCache(cachePath)
+	catalog.sleep = func(d time.Duration) { waits <- d }
+	return catalog, waits
+}
+
+// eventually waits for the background retry to make done true.
+func eventually(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoadOrFallbackServesCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heroes.json")
+	cached := liveHeroes[:2]
+	warm := NewCatalog(staticProvider(cached)).WithCache(path)
+	if err := warm.Load(); err != nil {
+		t.Fatalf("warm up cache: %v", err)
+	}
+
+	catalog, waits := fallbackCatalog(path)
+	if err := catalog.LoadOrFallback(); err != nil {
+		t.Fatalf("LoadOrFallback: %v", err)
+	}
+	if catalog.Source() != "cache" || len(catalog.All()) != len(cached) {
+		t.Fatalf("serving %d heroes from %q, want %d from the cache", len(catalog.All()), catalog.Source(), len(cached))
+	}
+	if !catalog.FetchedAt().Equal(warm.FetchedAt()) {
+		t.Errorf("FetchedAt = %v, want the original fetch %v", catalog.FetchedAt(), warm.FetchedAt())
+	}
+
+	if wait := <-waits; wait != retryMinInterval {
+		t.Errorf("first retry after %s, want %s", wait, retryMinInterval)
+	}
+	eventually(t, "the retry to reload the catalog and the cache", func() bool {
+		heroes, err := NewCacheProvider(path).Heroes()
+		return err == nil && len(heroes) == len(liveHeroes)
+	})
+	if catalog.Source() != "flaky" || len(catalog.All()) != len(liveHeroes) {
+		t.Errorf("after the retry: %d heroes from %q, want %d from the provider", len(catalog.All()), catalog.Source(), len(liveHeroes))
+	}
+}
+
+func TestLoadOrFallbackWithoutUsableCache(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache string // file contents; empty means no file
+	}{
+		{"missing", ""},
+		{"corrupt", `{"source": "opendota", "heroes": [`},
+		{"empty", `{"source": "opendota", "heroes": []}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "heroes.json")
+			if tt.cache != "" {
+				if err := os.WriteFile(path, []byte(tt.cache), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			catalog, waits := fallbackCatalog(path)
+			if err := catalog.LoadOrFallback(); err != nil {
+				t.Fatalf("LoadOrFallback: %v", err)
+			}
+			if catalog.Source() != "snapshot" || len(catalog.All()) < 100 {
+				t.Fatalf("serving %d heroes from %q, want the snapshot", len(catalog.All()), catalog.Source())
+			}
+
+			<-waits
+			// The successful retry refreshes the cache for the next start.
+			eventually(t, "the retry to rewrite the cache", func() bool {
+				heroes, err := NewCacheProvider(path).Heroes()
+				return err == nil && len(heroes) == len(liveHeroes)
+			})
+			if catalog.Source() != "flaky" {
+				t.Errorf("catalog source after the retry = %q, want the provider", catalog.Source())
+			}
+		})
+	}
+}
+
+func TestWriteCacheFailureLeavesNoPartialFile(t *testing.T) {
+	dir := t.TempDir()
+	// A directory in place of the cache file makes the final rename fail.
+	path := filepath.Join(dir, "heroes.json")
+	if err := os.MkdirAll(filepath.Join(path, "keep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeCache(path, cacheFile{Source: "opendota", FetchedAt: time.Now(), Heroes: liveHeroes})
+	if err == nil {
+		t.Fatal("expected writeCache to fail")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "heroes.json" {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Fatalf("directory holds %q after a failed write, want only the original entry", names)
+	}
+
+	// A cache that cannot be written does not fail the load itself.
+	catalog := NewCatalog(staticProvider(liveHeroes)).WithCache(path)
+	if err := catalog.Load(); err != nil {
+		t.Fatalf("Load with an unwritable cache: %v", err)
+	}
+}
+
+func TestRetryBackoffDoublesUpToMax(t *testing.T) {
+	provider := &flakyProvider{failures: 9, heroes: liveHeroes}
+	catalog := NewCatalog(provider)
+	var waits []time.Duration
+	catalog.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	catalog.retry()
+
+	want := []time.Duration{
+		5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second,
+		80 * time.Second, 160 * time.Second, 320 * time.Second,
+		10 * time.Minute, 10 * time.Minute, 10 * time.Minute,
+	}
+	if len(waits) != len(want) {
+		t.Fatalf("waits = %v, want %v", waits, want)
+	}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Fatalf("waits = %v, want %v", waits, want)
+		}
+	}
+	if provider.calls != 10 || catalog.Source() != "flaky" {
+		t.Errorf("%d provider calls, source %q; want 10 and the provider", provider.calls, catalog.Source())
+	}
+}
@@ -0,0 +1,297 @@
+package heroes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOpenDotaURL is the public OpenDota API root.
+const DefaultOpenDotaURL = "https://api.opendota.com/api"
+
+// matchupPrior is the number of virtual 50% games mixed into every matchup so
+// that pairs with a handful of games do not dominate the matrix.
+const matchupPrior = 20
+
+// Matchup is a single row of OpenDota's /heroes/{id}/matchups response. The
+// same shape is used for synergy rows, where games are played on one team.
+type Matchup struct {
+	HeroID      int `json:"hero_id"`
+	GamesPlayed int `json:"games_played"`
+	Wins        int `json:"wins"`
+}
+
+// Advantage returns the smoothed win rate of the owning hero minus 50%.
+func (m Matchup) Advantage() float64 {
+	return (float64(m.Wins)+matchupPrior/2)/(float64(m.GamesPlayed)+matchupPrior) - 0.5
+}
+
+// Matrix is an in-memory hero-vs-hero matrix of counter and synergy data.
+// It is safe for concurrent use.
+type Matrix struct {
+	mu        sync.RWMutex
+	counters  map[int]map[int]Matchup
+	synergies map[int]map[int]Matchup
+	fetchedAt time.Time
+}
+
+// NewMatrix returns an empty matrix.
+func NewMatrix() *Matrix {
+	return &Matrix{
+		counters:  make(map[int]map[int]Matchup),
+		synergies: make(map[int]map[int]Matchup),
+	}
+}
+
+// SetMatchups replaces the counter rows of a hero.
+func (m *Matrix) SetMatchups(heroID int, rows []Matchup) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[heroID] = indexRows(rows)
+	m.fetchedAt = time.Now()
+}
+
+// SetSynergies replaces the synergy rows of a hero.
+func (m *Matrix) SetSynergies(heroID int, rows []Matchup) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.synergies[heroID] = indexRows(rows)
+	m.fetchedAt = time.Now()
+}
+
+// Advantage reports how much better than 50% heroID does against enemyID.
+// Missing data yields 0.
+func (m *Matrix) Advantage(heroID, enemyID int) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return lookup(m.counters, heroID, enemyID, -1)
+}
+
+// Synergy reports how much better than 50% heroID does alongside allyID.
+// Missing data yields 0.
+func (m *Matrix) Synergy(heroID, allyID int) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return lookup(m.synergies, heroID, allyID, 1)
+}
+
+// Matchups returns the counter rows of a hero, best matchups first.
+func (m *Matrix) Matchups(heroID int) []Matchup {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return sortedRows(m.counters[heroID])
+}
+
+// Synergies returns the synergy rows of a hero, best partners first.
+func (m *Matrix) Synergies(heroID int) []Matchup {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return sortedRows(m.synergies[heroID])
+}
+
+// FetchedAt returns the time of the last update, zero if the matrix is empty.
+func (m *Matrix) FetchedAt() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.fetchedAt
+}
+
+func indexRows(rows []Matchup) map[int]Matchup {
+	index := make(map[int]Matchup, len(rows))
+	for _, row := range rows {
+		index[row.HeroID] = row
+	}
+	return index
+}
+
+// lookup falls back to the mirrored row when a has no data against b.
+// Counters flip sign when mirrored (mirror = -1), synergies do not (mirror = 1).
+func lookup(table map[int]map[int]Matchup, a, b int, mirror float64) float64 {
+	if row, ok := table[a][b]; ok {
+		return row.Advantage()
+	}
+	if row, ok := table[b][a]; ok {
+		return mirror * row.Advantage()
+	}
+	return 0
+}
+
+func sortedRows(index map[int]Matchup) []Matchup {
+	rows := make([]Matchup, 0, len(index))
+	for _, row := range index {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if ai, aj := rows[i].Advantage(), rows[j].Advantage(); ai != aj {
+			return ai > aj
+		}
+		return rows[i].HeroID < rows[j].HeroID
+	})
+	return rows
+}
+
+// MatchupConfig configures the matchup loader.
+type MatchupConfig struct {
+	// Catalog lists the heroes whose matchups are pulled.
+	Catalog *Catalog
+	// BaseURL is the OpenDota API root, e.g. https://api.opendota.com/api.
+	// Point it at a local stand-in server for tests.
+	BaseURL string
+	// SynergyURL optionally serves pairwise synergy rows as a JSON object
+	// keyed by hero id: {"1": [{"hero_id": 2, "games_played": 10, "wins": 6}]}.
+	// OpenDota has no such endpoint, so synergies are skipped when empty.
+	SynergyURL string
+	// RequestInterval throttles per-hero requests to respect API rate limits.
+	RequestInterval time.Duration
+	// RefreshInterval defaults to the hero catalog refresh interval.
+	RefreshInterval time.Duration
+}
+
+// StartMatchups returns a matrix that is filled in the background and then
+// refreshed periodically. The matrix is empty until the first load finishes.
+func StartMatchups(cfg MatchupConfig) *Matrix {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultOpenDotaURL
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = refreshInterval
+	}
+
+	matrix := NewMatrix()
+	go matchupRefresher(cfg, newHTTPClient(), matrix)
+	return matrix
+}
+
+func matchupRefresher(cfg MatchupConfig, client *http.Client, matrix *Matrix) {
+	loadMatchups(cfg, client, matrix)
+
+	ticker := time.NewTicker(cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		loadMatchups(cfg, client, matrix)
+	}
+}
+
+// loadMatchups pulls counter rows for every catalog hero and, if configured,
+// the synergy table. Failed heroes keep their previous rows.
+func loadMatchups(cfg MatchupConfig, client *http.Client, matrix *Matrix) {
+	failed := 0
+	for i, hero := range cfg.Catalog.All() {
+		if i > 0 && cfg.RequestInterval > 0 {
+			time.Sleep(cfg.RequestInterval)
+		}
+
+		var rows []Matchup
+		url := fmt.Sprintf("%s/heroes/%d/matchups", cfg.BaseURL, hero.ID)
+		if err := getJSON(client, url, &rows); err != nil {
+			failed++
+			log.Printf("heroes: failed to pull matchups for hero %d: %v", hero.ID, err)
+			continue
+		}
+		matrix.SetMatchups(hero.ID, rows)
+	}
+
+	if failed > 0 {
+		log.Printf("heroes: matchup refresh finished with %d failed heroes", failed)
+	}
+
+	if cfg.SynergyURL == "" {
+		return
+	}
+
+	var raw map[string][]Matchup
+	if err := getJSON(client, cfg.SynergyURL, &raw); err != nil {
+		log.Printf("heroes: failed to pull synergies: %v", err)
+		return
+	}
+	table, err := indexTable(raw)
+	if err != nil {
+		log.Printf("heroes: failed to parse synergies: %v", err)
+		return
+	}
+	for heroID, rows := range table {
+		matrix.SetSynergies(heroID, rows)
+	}
+}
+
+// MatchupFile is the offline form of the matrix: OpenDota matchup rows keyed
+// by hero id, plus optional synergy rows in the same shape.
+type MatchupFile struct {
+	Matchups  map[string][]Matchup `json:"matchups"`
+	Synergies map[string][]Matchup `json:"synergies"`
+}
+
+// LoadMatchupFile reads a matrix from a MatchupFile on disk. It lets bots and
+// tools run without network access.
+func LoadMatchupFile(path string) (*Matrix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadMatchups(f)
+}
+
+// ReadMatchups decodes a MatchupFile into a new matrix.
+func ReadMatchups(r io.Reader) (*Matrix, error) {
+	var file MatchupFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	counters, err := indexTable(file.Matchups)
+	if err != nil {
+		return nil, err
+	}
+	synergies, err := indexTable(file.Synergies)
+	if err != nil {
+		return nil, err
+	}
+	if len(counters) == 0 {
+		return nil, fmt.Errorf("matchup file has no matchups")
+	}
+
+	matrix := NewMatrix()
+	for heroID, rows := range counters {
+		matrix.SetMatchups(heroID, rows)
+	}
+	for heroID, rows := range synergies {
+		matrix.SetSynergies(heroID, rows)
+	}
+	return matrix, nil
+}
+
+func indexTable(raw map[string][]Matchup) (map[int][]Matchup, error) {
+	table := make(map[int][]Matchup, len(raw))
+	for key, rows := range raw {
+		heroID, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hero id %q", key)
+		}
+		table[heroID] = rows
+	}
+	return table, nil
+}
+
+func getJSON(client *http.Client, url string, dst any) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from %s: %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
@@ -0,0 +1,72 @@
+package heroes
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const matchupFixture = "testdata/matchups.json"
+
+func TestLoadMatchupFile(t *testing.T) {
+	matrix, err := LoadMatchupFile(matchupFixture)
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	// Anti-Mage vs Axe: 493 wins in 863 games, smoothed by 20 virtual games.
+	want := (493.0+10)/(863+20) - 0.5
+	if got := matrix.Advantage(1, 2); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Advantage(1, 2) = %v, want %v", got, want)
+	}
+	// Anti-Mage with Lion: 398 wins in 696 games together.
+	want = (398.0+10)/(696+20) - 0.5
+	if got := matrix.Synergy(1, 26); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Synergy(1, 26) = %v, want %v", got, want)
+	}
+	if got := matrix.Advantage(1, 999); got != 0 {
+		t.Errorf("Advantage against an unknown hero = %v, want 0", got)
+	}
+
+	rows := matrix.Matchups(1)
+	if len(rows) != 9 {
+		t.Fatalf("Matchups(1) has %d rows, want 9", len(rows))
+	}
+	for i := 1; i < len(rows); i++ {
+		if rows[i-1].Advantage() < rows[i].Advantage() {
+			t.Fatalf("Matchups(1) is not sorted best first: %+v", rows)
+		}
+	}
+	if rows[0].HeroID != 2 {
+		t.Errorf("best matchup of hero 1 = %d, want 2", rows[0].HeroID)
+	}
+	if matrix.FetchedAt().IsZero() {
+		t.Error("FetchedAt is zero after loading")
+	}
+}
+
+func TestMatrixMirrorsMissingRows(t *testing.T) {
+	matrix := NewMatrix()
+	matrix.SetMatchups(1, []Matchup{{HeroID: 2, GamesPlayed: 80, Wins: 50}})
+	matrix.SetSynergies(1, []Matchup{{HeroID: 3, GamesPlayed: 80, Wins: 50}})
+
+	advantage := matrix.Advantage(1, 2)
+	if advantage <= 0 {
+		t.Fatalf("Advantage(1, 2) = %v, want positive", advantage)
+	}
+	if got := matrix.Advantage(2, 1); got != -advantage {
+		t.Errorf("mirrored counter Advantage(2, 1) = %v, want %v", got, -advantage)
+	}
+	if got, want := matrix.Synergy(3, 1), matrix.Synergy(1, 3); got != want {
+		t.Errorf("mirrored Synergy(3, 1) = %v, want %v", got, want)
+	}
+}
+
+func TestReadMatchupsRejectsEmptyFile(t *testing.T) {
+	if _, err := ReadMatchups(strings.NewReader(`{"matchups": {}}`)); err == nil {
+		t.Fatal("expected an error for a file without matchups")
+	}
+	if _, err := ReadMatchups(strings.NewReader(`{"matchups": {"x": []}}`)); err == nil {
+		t.Fatal("expected an error for a non-numeric hero id")
+	}
+}
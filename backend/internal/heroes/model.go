@@ -10,12 +10,65 @@ type Hero struct {
 	Roles         []string `json:"roles"`
 	Img           string   `json:"img"`
 	Icon          string   `json:"icon"`
-	BaseHealth    int      `json:"base_health"`
-	BaseMana      int      `json:"base_mana"`
-	BaseArmor     float64  `json:"base_armor"`
-	BaseAttackMin int      `json:"base_attack_min"`
-	BaseAttackMax int      `json:"base_attack_max"`
-	MoveSpeed     int      `json:"move_speed"`
 	HeroID        int      `json:"hero_id"`
 	Legs          int      `json:"legs"`
+	// CMEnabled is OpenDota's Captains Mode flag; nil when the source does
+	// not report it, as the embedded snapshot does not.
+	CMEnabled *bool `json:"cm_enabled,omitempty"`
+
+	// Base attributes and their growth per level
+	BaseHealth      int     `json:"base_health"`
+	BaseHealthRegen float64 `json:"base_health_regen"`
+	BaseMana        int     `json:"base_mana"`
+	BaseManaRegen   float64 `json:"base_mana_regen"`
+	BaseArmor       float64 `json:"base_armor"`
+	BaseMR          int     `json:"base_mr"`
+	BaseStr         int     `json:"base_str"`
+	BaseAgi         int     `json:"base_agi"`
+	BaseInt         int     `json:"base_int"`
+	StrGain         float64 `json:"str_gain"`
+	AgiGain         float64 `json:"agi_gain"`
+	IntGain         float64 `json:"int_gain"`
+
+	// Attack and movement
+	BaseAttackMin   int     `json:"base_attack_min"`
+	BaseAttackMax   int     `json:"base_attack_max"`
+	AttackRange     int     `json:"attack_range"`
+	ProjectileSpeed int     `json:"projectile_speed"`
+	AttackRate      float64 `json:"attack_rate"`
+	BaseAttackTime  int     `json:"base_attack_time"`
+	AttackPoint     float64 `json:"attack_point"`
+	MoveSpeed       int     `json:"move_speed"`
+	TurnRate        float64 `json:"turn_rate"`
+	DayVision       int     `json:"day_vision"`
+	NightVision     int     `json:"night_vision"`
+
+	// Ranked picks and wins per rank bracket, Herald (1) to Immortal (8)
+	Pick1 int `json:"1_pick"`
+	Win1  int `json:"1_win"`
+	Pick2 int `json:"2_pick"`
+	Win2  int `json:"2_win"`
+	Pick3 int `json:"3_pick"`
+	Win3  int `json:"3_win"`
+	Pick4 int `json:"4_pick"`
+	Win4  int `json:"4_win"`
+	Pick5 int `json:"5_pick"`
+	Win5  int `json:"5_win"`
+	Pick6 int `json:"6_pick"`
+	Win6  int `json:"6_win"`
+	Pick7 int `json:"7_pick"`
+	Win7  int `json:"7_win"`
+	Pick8 int `json:"8_pick"`
+	Win8  int `json:"8_win"`
+
+	// Professional matches
+	ProPick int `json:"pro_pick"`
+	ProWin  int `json:"pro_win"`
+	ProBan  int `json:"pro_ban"`
+
+	// Turbo and all public matches
+	TurboPicks int `json:"turbo_picks"`
+	TurboWins  int `json:"turbo_wins"`
+	PubPick    int `json:"pub_pick"`
+	PubWin     int `json:"pub_win"`
 }
@@ -0,0 +1,154 @@
+package heroes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownPatch is returned for versions outside the patch table.
+var ErrUnknownPatch = errors.New("unknown patch")
+
+// Patch is a game version with its hero set. Heroes listed in CMDisabled
+// exist in the patch but cannot be picked or banned in Captains Mode, as
+// is usual for new heroes in their first weeks.
+type Patch struct {
+	Version    string `json:"version"`
+	Heroes     []int  `json:"heroes"`
+	CMDisabled []int  `json:"cmDisabled"`
+
+	cm map[int]bool
+}
+
+// InCM reports whether the hero can be drafted in Captains Mode on this patch.
+func (p Patch) InCM(heroID int) bool {
+	return p.cm[heroID]
+}
+
+//go:embed snapshot/patches.json
+var patchFile []byte
+
+// patches is the embedded patch table every catalog starts with, oldest first. Only patches that
+// changed the hero set or the CM-disabled list are listed.
+var patches = mustLoadPatches()
+
+func mustLoadPatches() []Patch {
+	var list []Patch
+	if err := json.Unmarshal(patchFile, &list); err != nil {
+		panic(fmt.Sprintf("heroes: embedded patch table is broken: %v", err))
+	}
+	for i := range list {
+		if _, err := parseVersion(list[i].Version); err != nil {
+			panic(fmt.Sprintf("heroes: embedded patch table is broken: %v", err))
+		}
+		list[i].cm = make(map[int]bool, len(list[i].Heroes))
+		for _, id := range list[i].Heroes {
+			list[i].cm[id] = true
+		}
+		for _, id := range list[i].CMDisabled {
+			delete(list[i].cm, id)
+		}
+	}
+	return list
+}
+
+// Patches returns the patch table, oldest first.
+func (c *Catalog) Patches() []Patch {
+	return append([]Patch(nil), c.patches...)
+}
+
+// LatestPatch returns the newest patch in the table, open-ended as
+// described in Patch.
+func (c *Catalog) LatestPatch() Patch {
+	return c.entry(len(c.patches) - 1)
+}
+
+// Patch returns the patch in effect at version: the newest listed patch not
+// newer than it, so "7.35" resolves to the rules of 7.33. An empty version
+// means the latest patch.
+//
+// The newest entry is open-ended: catalog heroes it does not mention at all
+// (released after the table was last updated) are draftable there unless the
+// provider reports cm_enabled=false for them, so a stale table never hides
+// new heroes. List them in cmDisabled of a new entry to keep them out of
+// Captains Mode regardless of the provider.
+func (c *Catalog) Patch(version string) (Patch, error) {
+	if version == "" {
+		return c.LatestPatch(), nil
+	}
+	want, err := parseVersion(version)
+	if err != nil {
+		return Patch{}, err
+	}
+	for i := len(c.patches) - 1; i >= 0; i-- {
+		if have, _ := parseVersion(c.patches[i].Version); !want.before(have) {
+			return c.entry(i), nil
+		}
+	}
+	return Patch{}, fmt.Errorf("%w %q: the table starts at %s", ErrUnknownPatch, version, c.patches[0].Version)
+}
+
+// entry returns the i-th patch of the table. The newest one additionally
+// allows catalog heroes the table does not know, following their cm_enabled
+// flag when the provider reports it.
+func (c *Catalog) entry(i int) Patch {
+	patch := c.patches[i]
+	if i != len(c.patches)-1 {
+		return patch
+	}
+	known := make(map[int]bool, len(patch.Heroes)+len(patch.CMDisabled))
+	for _, id := range patch.Heroes {
+		known[id] = true
+	}
+	for _, id := range patch.CMDisabled {
+		known[id] = true
+	}
+	cm := make(map[int]bool, len(patch.cm))
+	for id := range patch.cm {
+		cm[id] = true
+	}
+	for _, h := range c.All() {
+		if !known[h.ID] && (h.CMEnabled == nil || *h.CMEnabled) {
+			cm[h.ID] = true
+		}
+	}
+	patch.cm = cm
+	return patch
+}
+
+// version is a parsed game version such as 7.32e.
+type version struct {
+	major, minor int
+	letter       string
+}
+
+func parseVersion(s string) (version, error) {
+	invalid := fmt.Errorf("%w %q: expected a version like 7.32 or 7.32e", ErrUnknownPatch, s)
+	major, rest, ok := strings.Cut(s, ".")
+	if !ok {
+		return version{}, invalid
+	}
+	digits := strings.TrimRight(rest, "abcdefghijklmnopqrstuvwxyz")
+	v := version{letter: rest[len(digits):]}
+	var err error
+	if v.major, err = strconv.Atoi(major); err != nil {
+		return version{}, invalid
+	}
+	if v.minor, err = strconv.Atoi(digits); err != nil {
+		return version{}, invalid
+	}
+	return v, nil
+}
+
+func (v version) before(other version) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+	return v.letter < other.letter
+}
@@ -0,0 +1,94 @@
+package heroes
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// staticProvider serves a fixed hero list.
+type staticProvider []Hero
+
+func (p staticProvider) Name() string            { return "static" }
+func (p staticProvider) Heroes() ([]Hero, error) { return p, nil }
+
+func TestPatchResolvesToNewestNotLater(t *testing.T) {
+	catalog := SnapshotCatalog()
+
+	patch, err := catalog.Patch("7.35")
+	if err != nil {
+		t.Fatalf("Patch(7.35): %v", err)
+	}
+	if patch.Version != "7.33" {
+		t.Fatalf("7.35 resolved to %s, want 7.33", patch.Version)
+	}
+	// Muerta (138) was released in 7.32e and kept out of CM only there.
+	if disabled, _ := catalog.Patch("7.32e"); disabled.InCM(138) {
+		t.Error("hero 138 is draftable on 7.32e")
+	}
+	if !patch.InCM(138) {
+		t.Error("hero 138 is not draftable on 7.33")
+	}
+	if _, err := catalog.Patch("6.88"); err == nil {
+		t.Error("expected an error for a version before the table")
+	}
+}
+
+func TestLatestPatchAllowsHeroesMissingFromTable(t *testing.T) {
+	catalog := NewCatalog(staticProvider{
+		{ID: 1, Name: "npc_dota_hero_antimage"},
+		{ID: 900, Name: "npc_dota_hero_newcomer"},
+	})
+	if err := catalog.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	latest := catalog.LatestPatch()
+	if !latest.InCM(900) {
+		t.Errorf("hero unknown to the table is not draftable on %s", latest.Version)
+	}
+	if !latest.InCM(1) {
+		t.Errorf("hero 1 is not draftable on %s", latest.Version)
+	}
+	if latest.InCM(901) {
+		t.Error("hero outside the catalog is draftable")
+	}
+
+	older, err := catalog.Patch("7.33")
+	if err != nil {
+		t.Fatalf("Patch(7.33): %v", err)
+	}
+	if older.InCM(900) {
+		t.Error("older patches must not allow heroes they do not list")
+	}
+}
+
+func TestLatestPatchFollowsProviderCMFlag(t *testing.T) {
+	var list []Hero
+	err := json.Unmarshal([]byte(`[
+		{"id": 1, "name": "npc_dota_hero_antimage", "cm_enabled": true},
+		{"id": 900, "name": "npc_dota_hero_newcomer", "cm_enabled": false},
+		{"id": 901, "name": "npc_dota_hero_unreported"},
+		{"id": 902, "name": "npc_dota_hero_enabled", "cm_enabled": true}
+	]`), &list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := NewCatalog(staticProvider(list))
+	if err := catalog.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	latest := catalog.LatestPatch()
+	if latest.InCM(900) {
+		t.Error("new hero with cm_enabled=false is draftable on the latest patch")
+	}
+	if !latest.InCM(901) {
+		t.Error("new hero without cm_enabled is not draftable on the latest patch")
+	}
+	if !latest.InCM(902) {
+		t.Error("new hero with cm_enabled=true is not draftable on the latest patch")
+	}
+	if !latest.InCM(1) {
+		t.Error("hero 1 listed by the table is not draftable")
+	}
+}
@@ -0,0 +1,122 @@
+package heroes
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds every OpenDota request.
+const requestTimeout = 10 * time.Second
+
+// Provider is a source of hero data for a Catalog.
+type Provider interface {
+	// Name identifies the source in logs and errors.
+	Name() string
+	// Heroes returns the full hero list in the OpenDota heroStats shape.
+	Heroes() ([]Hero, error)
+}
+
+// datedProvider serves data fetched earlier (the disk cache, the embedded
+// snapshot) and knows when that was.
+type datedProvider interface {
+	fetchDated() ([]Hero, time.Time, error)
+}
+
+// fetch pulls heroes from p along with their fetch time: the original one
+// for dated providers, now for live sources.
+func fetch(p Provider) ([]Hero, time.Time, error) {
+	if dated, ok := p.(datedProvider); ok {
+		return dated.fetchDated()
+	}
+	heroes, err := p.Heroes()
+	return heroes, time.Now(), err
+}
+
+// OpenDotaProvider pulls heroes from the heroStats endpoint of an OpenDota
+// compatible API.
+type OpenDotaProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenDotaProvider returns a provider for the API rooted at baseURL.
+// An empty baseURL means DefaultOpenDotaURL; point it at a local stand-in
+// server for tests.
+func NewOpenDotaProvider(baseURL string) *OpenDotaProvider {
+	if baseURL == "" {
+		baseURL = DefaultOpenDotaURL
+	}
+	return &OpenDotaProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  newHTTPClient(),
+	}
+}
+
+func (p *OpenDotaProvider) Name() string { return "opendota" }
+
+func (p *OpenDotaProvider) Heroes() ([]Hero, error) {
+	var heroes []Hero
+	if err := getJSON(p.client, p.baseURL+"/heroStats", &heroes); err != nil {
+		return nil, err
+	}
+	return heroes, nil
+}
+
+//go:embed snapshot/heroes.json
+var snapshot []byte
+
+// SnapshotProvider serves the hero list embedded in the binary. The snapshot
+// carries the catalog basics (names, attributes, roles) without live stats.
+type SnapshotProvider struct{}
+
+// NewSnapshotProvider returns the embedded snapshot provider.
+func NewSnapshotProvider() SnapshotProvider {
+	return SnapshotProvider{}
+}
+
+func (SnapshotProvider) Name() string { return "snapshot" }
+
+func (p SnapshotProvider) Heroes() ([]Hero, error) {
+	heroes, _, err := p.fetchDated()
+	return heroes, err
+}
+
+func (SnapshotProvider) fetchDated() ([]Hero, time.Time, error) {
+	return readCache(bytes.NewReader(snapshot))
+}
+
+// FileProvider reads heroes from a local JSON file in the heroStats shape,
+// e.g. a saved OpenDota response.
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider returns a provider for the file at path.
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+func (p *FileProvider) Name() string { return p.path }
+
+func (p *FileProvider) Heroes() ([]Hero, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var heroes []Hero
+	if err := json.NewDecoder(f).Decode(&heroes); err != nil {
+		return nil, err
+	}
+	return heroes, nil
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
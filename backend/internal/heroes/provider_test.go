@@ -0,0 +1,138 @@
+package heroes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const heroStatsFixture = `[
+	{"id": 1, "name": "npc_dota_hero_antimage", "localized_name": "Anti-Mage", "primary_attr": "agi", "roles": ["Carry"], "pro_pick": 40, "pro_ban": 12},
+	{"id": 2, "name": "npc_dota_hero_axe", "localized_name": "Axe", "primary_attr": "str", "roles": ["Initiator"], "8_pick": 900, "8_win": 470}
+]`
+
+// openDotaServer stands in for the OpenDota API, answering /heroStats with
+// the given status and body.
+func openDotaServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/heroStats" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOpenDotaProvider(t *testing.T) {
+	srv := openDotaServer(t, http.StatusOK, heroStatsFixture)
+
+	// The trailing slash must not end up doubled in the request path.
+	provider := NewOpenDotaProvider(srv.URL + "/")
+	heroes, err := provider.Heroes()
+	if err != nil {
+		t.Fatalf("Heroes: %v", err)
+	}
+	if len(heroes) != 2 {
+		t.Fatalf("got %d heroes, want 2", len(heroes))
+	}
+	if heroes[0].LocalizedName != "Anti-Mage" || heroes[0].ProPick != 40 || heroes[0].ProBan != 12 {
+		t.Errorf("hero 1 decoded as %+v", heroes[0])
+	}
+	if heroes[1].Pick8 != 900 || heroes[1].Win8 != 470 {
+		t.Errorf("hero 2 bracket stats decoded as %d/%d, want 900/470", heroes[1].Pick8, heroes[1].Win8)
+	}
+
+	before := time.Now()
+	if _, fetchedAt, err := fetch(provider); err != nil || fetchedAt.Before(before) {
+		t.Errorf("live fetch: fetchedAt %v, err %v; want the current time", fetchedAt, err)
+	}
+}
+
+func TestOpenDotaProviderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"non-200", http.StatusServiceUnavailable, `{"error": "busy"}`, "503"},
+		{"bad json", http.StatusOK, `[{"id": "one"`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := openDotaServer(t, tt.status, tt.body)
+			heroes, err := NewOpenDotaProvider(srv.URL).Heroes()
+			if err == nil {
+				t.Fatalf("expected an error, got %d heroes", len(heroes))
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotProvider(t *testing.T) {
+	provider := NewSnapshotProvider()
+	heroes, fetchedAt, err := fetch(provider)
+	if err != nil {
+		t.Fatalf("fetch snapshot: %v", err)
+	}
+	if len(heroes) < 100 {
+		t.Fatalf("snapshot has %d heroes, want the full roster", len(heroes))
+	}
+	// The snapshot reports when it was captured, not when it was read.
+	if fetchedAt.IsZero() || fetchedAt.After(time.Now()) {
+		t.Errorf("snapshot fetchedAt = %v", fetchedAt)
+	}
+
+	seen := make(map[int]bool)
+	for _, h := range heroes {
+		if h.ID <= 0 || h.LocalizedName == "" || h.PrimaryAttr == "" {
+			t.Errorf("incomplete snapshot hero %+v", h)
+		}
+		if seen[h.ID] {
+			t.Errorf("duplicate snapshot hero %d", h.ID)
+		}
+		seen[h.ID] = true
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "heroStats.json")
+	if err := os.WriteFile(path, []byte(heroStatsFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewFileProvider(path)
+	if provider.Name() != path {
+		t.Errorf("Name() = %q, want the file path", provider.Name())
+	}
+	heroes, err := provider.Heroes()
+	if err != nil {
+		t.Fatalf("Heroes: %v", err)
+	}
+	if len(heroes) != 2 || heroes[1].Name != "npc_dota_hero_axe" {
+		t.Fatalf("decoded %+v", heroes)
+	}
+
+	if _, err := NewFileProvider(filepath.Join(dir, "missing.json")).Heroes(); !os.IsNotExist(err) {
+		t.Errorf("missing file: err = %v, want not exist", err)
+	}
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileProvider(broken).Heroes(); err == nil {
+		t.Error("expected an error for a file that is not JSON")
+	}
+}
@@ -0,0 +1,188 @@
+package heroes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Filter narrows a hero list. Empty fields match every hero.
+type Filter struct {
+	// Query is a hero name, a prefix of it, a community alias ("am", "wr")
+	// or a slightly misspelled name.
+	Query string
+	// PrimaryAttr is str, agi, int or all.
+	PrimaryAttr string
+	// AttackType is Melee or Ranged, case-insensitive.
+	AttackType string
+	// Role is one of the OpenDota roles, e.g. Carry or Support, case-insensitive.
+	Role string
+}
+
+// Relevance tiers of a query match, best first.
+const (
+	matchExact = iota
+	matchPrefix
+	matchWordPrefix
+	matchFuzzy
+	noMatch
+)
+
+// Search returns the heroes matching the filter. With a query the best
+// matches come first, otherwise the list order is kept.
+func Search(list []Hero, f Filter) []Hero {
+	type scored struct {
+		hero  Hero
+		score int
+	}
+
+	query := normalize(f.Query)
+	var matches []scored
+	for _, h := range list {
+		if f.PrimaryAttr != "" && !strings.EqualFold(h.PrimaryAttr, f.PrimaryAttr) {
+			continue
+		}
+		if f.AttackType != "" && !strings.EqualFold(h.AttackType, f.AttackType) {
+			continue
+		}
+		if f.Role != "" && !hasRole(h, f.Role) {
+			continue
+		}
+		score := matchExact
+		if query != "" {
+			score = matchScore(h, query)
+		}
+		if score == noMatch {
+			continue
+		}
+		matches = append(matches, scored{hero: h, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+	result := make([]Hero, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.hero)
+	}
+	return result
+}
+
+// Resolve finds the hero a player means by name: an alias, the full or
+// internal name, a unique prefix or a unique close misspelling.
+func (c *Catalog) Resolve(name string) (Hero, error) {
+	query := normalize(name)
+	if query == "" {
+		return Hero{}, fmt.Errorf("empty hero name")
+	}
+
+	best, candidates := noMatch, []Hero(nil)
+	for _, h := range c.All() {
+		switch score := matchScore(h, query); {
+		case score < best:
+			best, candidates = score, []Hero{h}
+		case score == best && score != noMatch:
+			candidates = append(candidates, h)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return Hero{}, fmt.Errorf("no hero matches %q", name)
+	case 1:
+		return candidates[0], nil
+	default:
+		names := make([]string, 0, len(candidates))
+		for _, h := range candidates {
+			names = append(names, h.LocalizedName)
+		}
+		return Hero{}, fmt.Errorf("%q is ambiguous: %s", name, strings.Join(names, ", "))
+	}
+}
+
+// matchScore rates how well a normalized query names the hero.
+func matchScore(h Hero, query string) int {
+	if id, ok := aliases[query]; ok && id == h.ID {
+		return matchExact
+	}
+
+	name := normalize(h.LocalizedName)
+	key := normalize(strings.TrimPrefix(h.Name, "npc_dota_hero_"))
+	switch {
+	case query == name || query == key:
+		return matchExact
+	case strings.HasPrefix(name, query) || strings.HasPrefix(key, query):
+		return matchPrefix
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(h.LocalizedName), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if strings.HasPrefix(word, query) {
+			return matchWordPrefix
+		}
+	}
+
+	// Typos are only forgiven in queries long enough to be a name.
+	if len(query) < 4 {
+		return noMatch
+	}
+	limit := 1
+	if len(query) >= 7 {
+		limit = 2
+	}
+	candidates := append([]string{name}, words...)
+	for _, candidate := range candidates {
+		// Compare with the beginning of the name, so "invok" and "invokr" both count.
+		if len(candidate) > len(query)+limit {
+			candidate = candidate[:len(query)+limit]
+		}
+		if distance(query, candidate) <= limit {
+			return matchFuzzy
+		}
+	}
+	return noMatch
+}
+
+func hasRole(h Hero, role string) bool {
+	for _, r := range h.Roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lowercases s and drops everything but letters and digits,
+// so "Nature's Prophet" and "natures prophet" compare equal.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// distance is the Levenshtein edit distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,94 @@
+package heroes
+
+import "testing"
+
+var searchHeroes = []Hero{
+	{ID: 17, Name: "npc_dota_hero_storm_spirit", LocalizedName: "Storm Spirit", PrimaryAttr: "int", AttackType: "Ranged", Roles: []string{"Carry", "Escape"}},
+	{ID: 107, Name: "npc_dota_hero_earth_spirit", LocalizedName: "Earth Spirit", PrimaryAttr: "str", AttackType: "Melee", Roles: []string{"Nuker", "Escape"}},
+	{ID: 71, Name: "npc_dota_hero_spirit_breaker", LocalizedName: "Spirit Breaker", PrimaryAttr: "str", AttackType: "Melee", Roles: []string{"Carry", "Initiator"}},
+	{ID: 67, Name: "npc_dota_hero_spectre", LocalizedName: "Spectre", PrimaryAttr: "agi", AttackType: "Melee", Roles: []string{"Carry"}},
+	{ID: 74, Name: "npc_dota_hero_invoker", LocalizedName: "Invoker", PrimaryAttr: "all", AttackType: "Ranged", Roles: []string{"Carry", "Nuker"}},
+	{ID: 53, Name: "npc_dota_hero_furion", LocalizedName: "Nature's Prophet", PrimaryAttr: "int", AttackType: "Ranged", Roles: []string{"Carry", "Pusher"}},
+	{ID: 2, Name: "npc_dota_hero_axe", LocalizedName: "Axe", PrimaryAttr: "str", AttackType: "Melee", Roles: []string{"Initiator"}},
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int
+	}{
+		{"no filter keeps order", Filter{}, []int{17, 107, 71, 67, 74, 53, 2}},
+		{"exact", Filter{Query: "Axe"}, []int{2}},
+		{"exact ignores punctuation", Filter{Query: "natures prophet"}, []int{53}},
+		{"internal name", Filter{Query: "furion"}, []int{53}},
+		{"alias", Filter{Query: "ES"}, nil}, // alias of Earthshaker, who is not in the list
+		{"alias in list", Filter{Query: "sb"}, []int{71}},
+		{"prefix", Filter{Query: "spe"}, []int{67}},
+		{"prefix before word prefix", Filter{Query: "spirit"}, []int{71, 17, 107}},
+		{"word prefix", Filter{Query: "proph"}, []int{53}},
+		{"levenshtein one typo", Filter{Query: "invokr"}, []int{74}},
+		{"levenshtein two typos in a long name", Filter{Query: "spcetre"}, []int{67}},
+		{"short queries are not fuzzy", Filter{Query: "axx"}, nil},
+		{"too many typos", Filter{Query: "ivxkxr"}, nil},
+		{"attribute", Filter{PrimaryAttr: "STR"}, []int{107, 71, 2}},
+		{"attack type and role", Filter{AttackType: "ranged", Role: "carry"}, []int{17, 74, 53}},
+		{"query with filter", Filter{Query: "spirit", PrimaryAttr: "int"}, []int{17}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, h := range Search(searchHeroes, tt.filter) {
+				got = append(got, h.ID)
+			}
+			if !equalIDs(got, tt.want) {
+				t.Errorf("Search(%+v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchScoreTiers(t *testing.T) {
+	invoker := searchHeroes[4]
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"invoker", matchExact},
+		{"invo", matchExact}, // alias
+		{"invok", matchPrefix},
+		{"invoer", matchFuzzy},
+		{"voker", matchExact}, // alias
+		{"oker", noMatch},
+	}
+	for _, tt := range tests {
+		if got := matchScore(invoker, tt.query); got != tt.want {
+			t.Errorf("matchScore(Invoker, %q) = %d, want %d", tt.query, got, tt.want)
+		}
+	}
+	if got := matchScore(searchHeroes[0], "spirit"); got != matchWordPrefix {
+		t.Errorf("matchScore(Storm Spirit, spirit) = %d, want word prefix", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "axe", 3},
+		{"axe", "axe", 0},
+		{"kitten", "sitting", 3},
+		{"invokr", "invoke", 1},
+		{"abc", "acb", 2},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package heroes
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Bracket selects the matches hero statistics are taken from.
+type Bracket string
+
+const (
+	BracketHerald   Bracket = "herald"
+	BracketGuardian Bracket = "guardian"
+	BracketCrusader Bracket = "crusader"
+	BracketArchon   Bracket = "archon"
+	BracketLegend   Bracket = "legend"
+	BracketAncient  Bracket = "ancient"
+	BracketDivine   Bracket = "divine"
+	BracketImmortal Bracket = "immortal"
+	// BracketRanked sums all eight rank brackets.
+	BracketRanked Bracket = "ranked"
+	BracketPro    Bracket = "pro"
+	BracketTurbo  Bracket = "turbo"
+)
+
+// rankBrackets lists the rank brackets in OpenDota order (1_pick … 8_pick).
+var rankBrackets = []Bracket{
+	BracketHerald, BracketGuardian, BracketCrusader, BracketArchon,
+	BracketLegend, BracketAncient, BracketDivine, BracketImmortal,
+}
+
+// ParseBracket validates a bracket name.
+func ParseBracket(name string) (Bracket, error) {
+	switch b := Bracket(name); b {
+	case BracketRanked, BracketPro, BracketTurbo:
+		return b, nil
+	default:
+		for _, rank := range rankBrackets {
+			if b == rank {
+				return b, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unknown bracket %q", name)
+}
+
+// Counts returns the picks, wins and bans of the hero in a bracket.
+// OpenDota reports bans for professional matches only.
+func (h Hero) Counts(b Bracket) (picks, wins, bans int) {
+	ranked := [][2]int{
+		{h.Pick1, h.Win1}, {h.Pick2, h.Win2}, {h.Pick3, h.Win3}, {h.Pick4, h.Win4},
+		{h.Pick5, h.Win5}, {h.Pick6, h.Win6}, {h.Pick7, h.Win7}, {h.Pick8, h.Win8},
+	}
+	switch b {
+	case BracketPro:
+		return h.ProPick, h.ProWin, h.ProBan
+	case BracketTurbo:
+		return h.TurboPicks, h.TurboWins, 0
+	case BracketRanked:
+		for _, row := range ranked {
+			picks += row[0]
+			wins += row[1]
+		}
+		return picks, wins, 0
+	}
+	for i, rank := range rankBrackets {
+		if b == rank {
+			return ranked[i][0], ranked[i][1], 0
+		}
+	}
+	return 0, 0, 0
+}
+
+// Meta is the strength of a hero in one bracket.
+type Meta struct {
+	Bracket Bracket `json:"bracket"`
+	Picks   int     `json:"picks"`
+	Wins    int     `json:"wins"`
+	Bans    int     `json:"bans,omitempty"`
+	// WinRate is wins per pick; PickRate is the share of the bracket's
+	// matches the hero was picked in.
+	WinRate  float64 `json:"winRate"`
+	PickRate float64 `json:"pickRate"`
+	// BanRate and ContestRate (picked or banned) are known for pro matches only.
+	BanRate     float64 `json:"banRate,omitempty"`
+	ContestRate float64 `json:"contestRate,omitempty"`
+	// Matches is the number of matches in the bracket the rates are relative
+	// to. OpenDota does not report it, so it is estimated as all picks of
+	// the catalog over ten players, and MatchesEstimated says so.
+	Matches          float64 `json:"matches"`
+	MatchesEstimated bool    `json:"matchesEstimated"`
+}
+
+// RatedHero is a hero together with its meta in the requested bracket.
+type RatedHero struct {
+	Hero
+	Meta Meta `json:"meta"`
+}
+
+// Rate computes bracket metrics for every hero in the list. The number of
+// matches in a bracket is estimated as all picks in catalog over ten players,
+// so a filtered list keeps the rates of the full catalog.
+func Rate(list, catalog []Hero, bracket Bracket) []RatedHero {
+	totalPicks := 0
+	for _, h := range catalog {
+		picks, _, _ := h.Counts(bracket)
+		totalPicks += picks
+	}
+	matches := float64(totalPicks) / 10
+
+	rated := make([]RatedHero, 0, len(list))
+	for _, h := range list {
+		picks, wins, bans := h.Counts(bracket)
+		meta := Meta{
+			Bracket: bracket, Picks: picks, Wins: wins, Bans: bans,
+			Matches: matches, MatchesEstimated: true,
+		}
+		if picks > 0 {
+			meta.WinRate = float64(wins) / float64(picks)
+		}
+		if matches > 0 {
+			meta.PickRate = float64(picks) / matches
+			meta.BanRate = float64(bans) / matches
+			if bracket == BracketPro {
+				meta.ContestRate = float64(picks+bans) / matches
+			}
+		}
+		rated = append(rated, RatedHero{Hero: h, Meta: meta})
+	}
+	return rated
+}
+
+// Sort keys accepted by SortRated.
+var sortKeys = map[string]func(Meta) float64{
+	"winrate":     func(m Meta) float64 { return m.WinRate },
+	"pickrate":    func(m Meta) float64 { return m.PickRate },
+	"banrate":     func(m Meta) float64 { return m.BanRate },
+	"contestrate": func(m Meta) float64 { return m.ContestRate },
+	"picks":       func(m Meta) float64 { return float64(m.Picks) },
+}
+
+// SortRated orders heroes by a meta metric, strongest first; ties keep
+// ascending hero id order.
+func SortRated(rated []RatedHero, key string) error {
+	metric, ok := sortKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown sort key %q", key)
+	}
+	sort.SliceStable(rated, func(i, j int) bool {
+		if a, b := metric(rated[i].Meta), metric(rated[j].Meta); a != b {
+			return a > b
+		}
+		return rated[i].ID < rated[j].ID
+	})
+	return nil
+}
@@ -0,0 +1,155 @@
+package heroes
+
+import (
+	"math"
+	"testing"
+)
+
+const heroStatsFile = "testdata/herostats.json"
+
+// statsFixture loads four heroes with known per-bracket counts; hero 3 has
+// no games at all.
+func statsFixture(t *testing.T) []Hero {
+	t.Helper()
+	heroes, err := NewFileProvider(heroStatsFile).Heroes()
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	return heroes
+}
+
+func TestParseBracket(t *testing.T) {
+	tests := []struct {
+		name string
+		want Bracket
+		ok   bool
+	}{
+		{"herald", BracketHerald, true},
+		{"immortal", BracketImmortal, true},
+		{"ranked", BracketRanked, true},
+		{"pro", BracketPro, true},
+		{"turbo", BracketTurbo, true},
+		{"Herald", "", false},
+		{"mythic", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, err := ParseBracket(tt.name)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseBracket(%q) = %q, %v; want %q, ok %v", tt.name, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	list := statsFixture(t)
+
+	tests := []struct {
+		bracket Bracket
+		heroID  int
+		want    Meta
+	}{
+		// 700 ranked picks in total: 70 matches
+		{BracketRanked, 1, Meta{Picks: 150, Wins: 80, WinRate: 80.0 / 150, PickRate: 150.0 / 70, Matches: 70}},
+		{BracketRanked, 3, Meta{Matches: 70}},
+		// 500 Herald picks: 50 matches
+		{BracketHerald, 2, Meta{Picks: 300, Wins: 140, WinRate: 140.0 / 300, PickRate: 300.0 / 50, Matches: 50}},
+		{BracketTurbo, 4, Meta{Picks: 100, Wins: 40, WinRate: 0.4, PickRate: 100.0 / 30, Matches: 30}},
+		// 60 pro picks: 6 matches; only pro matches report bans
+		{BracketPro, 1, Meta{
+			Picks: 30, Wins: 18, Bans: 20, WinRate: 0.6, PickRate: 30.0 / 6,
+			BanRate: 20.0 / 6, ContestRate: 50.0 / 6, Matches: 6,
+		}},
+		{BracketPro, 2, Meta{Picks: 10, Wins: 4, WinRate: 0.4, PickRate: 10.0 / 6, ContestRate: 10.0 / 6, Matches: 6}},
+	}
+	for _, tt := range tests {
+		rated := Rate(list, list, tt.bracket)
+		var got Meta
+		for _, r := range rated {
+			if r.ID == tt.heroID {
+				got = r.Meta
+			}
+		}
+		tt.want.Bracket = tt.bracket
+		tt.want.MatchesEstimated = true
+		if !metaEqual(got, tt.want) {
+			t.Errorf("%s hero %d: meta = %+v, want %+v", tt.bracket, tt.heroID, got, tt.want)
+		}
+	}
+}
+
+func TestRateFilteredListUsesCatalogMatches(t *testing.T) {
+	list := statsFixture(t)
+
+	rated := Rate(list[1:2], list, BracketRanked)
+	if len(rated) != 1 || rated[0].ID != 2 {
+		t.Fatalf("rated %+v, want only hero 2", rated)
+	}
+	if got := rated[0].Meta; got.Matches != 70 || !almostEqual(got.PickRate, 450.0/70) {
+		t.Errorf("filtered meta = %+v, want the pick rate over all 70 matches", got)
+	}
+
+	for _, r := range Rate(list, nil, BracketRanked) {
+		if r.Meta.PickRate != 0 || r.Meta.Matches != 0 {
+			t.Errorf("hero %d rated without a catalog: %+v", r.ID, r.Meta)
+		}
+	}
+}
+
+func TestSortRated(t *testing.T) {
+	list := statsFixture(t)
+
+	tests := []struct {
+		bracket Bracket
+		key     string
+		want    []int
+	}{
+		{BracketRanked, "winrate", []int{1, 2, 4, 3}},
+		{BracketRanked, "picks", []int{2, 1, 4, 3}},
+		{BracketRanked, "pickrate", []int{2, 1, 4, 3}},
+		// Nobody is banned outside pro matches: ties keep id order.
+		{BracketRanked, "banrate", []int{1, 2, 3, 4}},
+		{BracketPro, "banrate", []int{4, 1, 2, 3}},
+		{BracketPro, "contestrate", []int{4, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		rated := Rate(list, list, tt.bracket)
+		if err := SortRated(rated, tt.key); err != nil {
+			t.Fatalf("SortRated(%s): %v", tt.key, err)
+		}
+		var got []int
+		for _, r := range rated {
+			got = append(got, r.ID)
+		}
+		if !equalIDs(got, tt.want) {
+			t.Errorf("%s by %s = %v, want %v", tt.bracket, tt.key, got, tt.want)
+		}
+	}
+
+	if err := SortRated(Rate(list, list, BracketPro), "kda"); err == nil {
+		t.Error("expected an error for an unknown sort key")
+	}
+}
+
+func metaEqual(a, b Meta) bool {
+	return a.Bracket == b.Bracket && a.Picks == b.Picks && a.Wins == b.Wins && a.Bans == b.Bans &&
+		a.MatchesEstimated == b.MatchesEstimated && almostEqual(a.Matches, b.Matches) &&
+		almostEqual(a.WinRate, b.WinRate) && almostEqual(a.PickRate, b.PickRate) &&
+		almostEqual(a.BanRate, b.BanRate) && almostEqual(a.ContestRate, b.ContestRate)
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
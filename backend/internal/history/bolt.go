@@ -0,0 +1,92 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/example/draftpractice/internal/draft"
+	bolt "go.etcd.io/bbolt"
+)
+
+var draftsBucket = []byte("drafts")
+
+// BoltArchive — Archive во встроенной базе bbolt. Сводки хранятся как JSON
+// по id драфта; фильтрация выполняется в памяти после чтения.
+type BoltArchive struct {
+	db *bolt.DB
+}
+
+// OpenBoltArchive открывает (или создаёт) файл архива.
+func OpenBoltArchive(path string) (*BoltArchive, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(draftsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltArchive{db: db}, nil
+}
+
+// Close закрывает базу.
+func (a *BoltArchive) Close() error {
+	return a.db.Close()
+}
+
+// Add сохраняет сводку (повторная запись того же драфта заменяет прежнюю).
+func (a *BoltArchive) Add(summary Summary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	return a.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(draftsBucket).Put([]byte(summary.ID), data)
+	})
+}
+
+// SetWinner записывает исход игры в сводку id.
+func (a *BoltArchive) SetWinner(id string, winner draft.Side) error {
+	return a.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(draftsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		var summary Summary
+		if err := json.Unmarshal(data, &summary); err != nil {
+			return err
+		}
+		summary.Winner = winner
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(id), data)
+	})
+}
+
+// List возвращает все сводки, от новых к старым.
+func (a *BoltArchive) List() ([]Summary, error) {
+	var summaries []Summary
+	err := a.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(draftsBucket).ForEach(func(_, data []byte) error {
+			var summary Summary
+			if err := json.Unmarshal(data, &summary); err != nil {
+				return err
+			}
+			summaries = append(summaries, summary)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortNewestFirst(summaries)
+	return summaries, nil
+}
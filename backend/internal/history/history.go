@@ -0,0 +1,161 @@
+// Package history хранит сводки завершённых драфтов и отвечает на запросы
+// тренеров: поиск по фильтрам и агрегаты пиков/банов.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/example/draftpractice/internal/analytics"
+	"github.com/example/draftpractice/internal/draft"
+)
+
+var (
+	// ErrInvalidCursor — курсор не соответствует ни одной записи выборки.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrNotFound — в архиве нет сводки с таким id.
+	ErrNotFound = errors.New("draft not found in history")
+)
+
+// Summary — сводка завершённого драфта.
+type Summary struct {
+	ID          string     `json:"id"`
+	Format      string     `json:"format"`
+	Patch       string     `json:"patch,omitempty"`
+	Radiant     draft.Team `json:"radiant"`
+	Dire        draft.Team `json:"dire"`
+	FirstPick   draft.Side `json:"firstPick"`
+	BotSide     draft.Side `json:"botSide,omitempty"`
+	BotType     string     `json:"botType,omitempty"`
+	CompletedAt time.Time  `json:"completedAt"`
+	// Оценка модели; nil — модель не была настроена
+	RadiantWinProbability *float64 `json:"radiantWinProbability,omitempty"`
+	// Сторона, выигравшая игру; пустая — исход не записан (драфт вне серии
+	// или следующая игра серии ещё не начата)
+	Winner draft.Side `json:"winner,omitempty"`
+}
+
+// Favorite возвращает сторону, которую модель считает фаворитом ("" — без оценки).
+func (s Summary) Favorite() draft.Side {
+	if s.RadiantWinProbability == nil {
+		return ""
+	}
+	if *s.RadiantWinProbability >= 0.5 {
+		return draft.SideRadiant
+	}
+	return draft.SideDire
+}
+
+// NewSummary собирает сводку по завершённой сессии. model может быть nil.
+func NewSummary(session draft.DraftSession, model *analytics.Model) Summary {
+	summary := Summary{
+		ID:          session.ID,
+		Format:      session.Format,
+		Patch:       session.Patch,
+		Radiant:     session.Radiant,
+		Dire:        session.Dire,
+		FirstPick:   session.FirstPick,
+		BotSide:     session.BotSide,
+		BotType:     session.BotType,
+		CompletedAt: time.Now().UTC(),
+	}
+	if session.BotSide == "" {
+		summary.BotType = ""
+	}
+	if n := len(session.Events); n > 0 {
+		summary.CompletedAt = session.Events[n-1].At
+	}
+	if model != nil {
+		p := analytics.Evaluate(model, &session).RadiantWinProbability
+		summary.RadiantWinProbability = &p
+	}
+	return summary
+}
+
+// Archive — хранилище сводок завершённых драфтов.
+type Archive interface {
+	Add(summary Summary) error
+	// List возвращает все сводки, от новых к старым.
+	List() ([]Summary, error)
+	// SetWinner записывает исход игры в сводку id (ErrNotFound, если её нет).
+	SetWinner(id string, winner draft.Side) error
+}
+
+// Recorder возвращает обработчик для draft.Store.OnCompleted, который
+// пишет сводку каждого завершённого драфта в архив.
+func Recorder(archive Archive, model *analytics.Model) func(draft.DraftSession) {
+	return func(session draft.DraftSession) {
+		if err := archive.Add(NewSummary(session, model)); err != nil {
+			fmt.Printf("[HISTORY] failed to record draft %s: %v\n", session.ID, err)
+		}
+	}
+}
+
+// ResultRecorder возвращает обработчик для draft.Store.OnResult, который
+// дописывает исход игры в сводку её драфта.
+func ResultRecorder(archive Archive) func(draft.GameResult) {
+	return func(result draft.GameResult) {
+		if err := archive.SetWinner(result.SessionID, result.Winner); err != nil {
+			fmt.Printf("[HISTORY] failed to record result of draft %s: %v\n", result.SessionID, err)
+		}
+	}
+}
+
+// MemoryArchive — Archive в памяти процесса.
+type MemoryArchive struct {
+	mu        sync.RWMutex
+	summaries map[string]Summary
+}
+
+// NewMemoryArchive создаёт пустой MemoryArchive.
+func NewMemoryArchive() *MemoryArchive {
+	return &MemoryArchive{summaries: make(map[string]Summary)}
+}
+
+// Add сохраняет сводку (повторная запись того же драфта заменяет прежнюю).
+func (a *MemoryArchive) Add(summary Summary) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.summaries[summary.ID] = summary
+	return nil
+}
+
+// SetWinner записывает исход игры в сводку id.
+func (a *MemoryArchive) SetWinner(id string, winner draft.Side) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	summary, ok := a.summaries[id]
+	if !ok {
+		return ErrNotFound
+	}
+	summary.Winner = winner
+	a.summaries[id] = summary
+	return nil
+}
+
+// List возвращает все сводки, от новых к старым.
+func (a *MemoryArchive) List() ([]Summary, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	summaries := make([]Summary, 0, len(a.summaries))
+	for _, s := range a.summaries {
+		summaries = append(summaries, s)
+	}
+	sortNewestFirst(summaries)
+	return summaries, nil
+}
+
+// sortNewestFirst упорядочивает сводки по времени завершения, при равенстве — по id.
+func sortNewestFirst(summaries []Summary) {
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if !a.CompletedAt.Equal(b.CompletedAt) {
+			return a.CompletedAt.After(b.CompletedAt)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,214 @@
+package history
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/draftpractice/internal/draft"
+)
+
+var (
+	day1 = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	day2 = day1.AddDate(0, 0, 1)
+	day3 = day1.AddDate(0, 0, 2)
+)
+
+func probability(p float64) *float64 { return &p }
+
+// testSummaries — три драфта по дню каждый:
+//
+//	s1: Liquid (Radiant) — Spirit, победа Dire, модель за Radiant
+//	s2: Spirit (Radiant) — OG в формате cd, победа Radiant, модель за Dire
+//	s3: OG (Radiant) — Liquid, без исхода и без оценки
+func testSummaries() []Summary {
+	return []Summary{
+		{
+			ID: "s1", Format: "cm_7_34", CompletedAt: day1,
+			Radiant:               draft.Team{Name: "Liquid", Picks: []int{1, 2}, Bans: []int{10}},
+			Dire:                  draft.Team{Name: "Spirit", Picks: []int{3}, Bans: []int{11}},
+			RadiantWinProbability: probability(0.7),
+			Winner:                draft.SideDire,
+		},
+		{
+			ID: "s2", Format: "cd", CompletedAt: day2,
+			Radiant:               draft.Team{Name: "Spirit", Picks: []int{3, 4}, Bans: []int{1}},
+			Dire:                  draft.Team{Name: "OG", Picks: []int{5}, Bans: []int{12}},
+			RadiantWinProbability: probability(0.4),
+			Winner:                draft.SideRadiant,
+		},
+		{
+			ID: "s3", Format: "cm_7_34", CompletedAt: day3,
+			Radiant: draft.Team{Name: "OG", Picks: []int{6}},
+			Dire:    draft.Team{Name: "Liquid", Picks: []int{1, 7}, Bans: []int{3}},
+		},
+	}
+}
+
+func testArchive(t *testing.T) *MemoryArchive {
+	t.Helper()
+	archive := NewMemoryArchive()
+	for _, s := range testSummaries() {
+		if err := archive.Add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return archive
+}
+
+func ids(summaries []Summary) []string {
+	result := []string{}
+	for _, s := range summaries {
+		result = append(result, s.ID)
+	}
+	return result
+}
+
+func TestSearchFilters(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all", Query{}, []string{"s3", "s2", "s1"}},
+		{"hero picked or banned", Query{HeroID: 1}, []string{"s3", "s2", "s1"}},
+		{"hero on side", Query{HeroID: 1, Side: draft.SideRadiant}, []string{"s2", "s1"}},
+		{"team", Query{Team: "liquid"}, []string{"s3", "s1"}},
+		{"team on side", Query{Team: "Liquid", Side: draft.SideDire}, []string{"s3"}},
+		{"format", Query{Format: "cd"}, []string{"s2"}},
+		{"result win", Query{Team: "Spirit", Result: ResultWin}, []string{"s2", "s1"}},
+		{"result loss", Query{Team: "Liquid", Result: ResultLoss}, []string{"s1"}},
+		{"result without outcome", Query{Team: "Liquid", Result: ResultWin}, []string{}},
+		{"result by side", Query{Side: draft.SideRadiant, Result: ResultWin}, []string{"s2"}},
+		{"favored", Query{Team: "OG", Favored: &yes}, []string{"s2"}},
+		{"not favored", Query{Team: "Spirit", Favored: &no}, []string{"s2", "s1"}},
+		{"from is inclusive", Query{From: day2}, []string{"s3", "s2"}},
+		{"to is exclusive", Query{To: day3}, []string{"s2", "s1"}},
+		{"from and to", Query{From: day2, To: day3}, []string{"s2"}},
+	}
+	archive := testArchive(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Search(archive, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(page.Drafts); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("drafts %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	archive := testArchive(t)
+
+	page, err := Search(archive, Query{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(page.Drafts); !reflect.DeepEqual(got, []string{"s3", "s2"}) || page.NextCursor != "s2" {
+		t.Fatalf("first page %v, cursor %q", got, page.NextCursor)
+	}
+
+	page, err = Search(archive, Query{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(page.Drafts); !reflect.DeepEqual(got, []string{"s1"}) || page.NextCursor != "" {
+		t.Fatalf("last page %v, cursor %q", got, page.NextCursor)
+	}
+
+	// Курсор считается внутри отфильтрованной выборки
+	page, err = Search(archive, Query{Team: "Liquid", Limit: 1, Cursor: "s3"})
+	if err != nil || !reflect.DeepEqual(ids(page.Drafts), []string{"s1"}) {
+		t.Fatalf("filtered page %v, %v", ids(page.Drafts), err)
+	}
+	if _, err := Search(archive, Query{Team: "Liquid", Cursor: "s2"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("cursor outside the selection: %v", err)
+	}
+	if _, err := Search(archive, Query{Cursor: "missing"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("unknown cursor: %v", err)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	archive := testArchive(t)
+
+	stats, err := Aggregate(archive, Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Drafts != 3 || len(stats.Heroes) == 0 {
+		t.Fatalf("stats %+v", stats)
+	}
+	// Герой 1 — два пика и бан из трёх драфтов, самый спорный
+	top := stats.Heroes[0]
+	want := HeroStats{HeroID: 1, Picks: 2, Bans: 1, PickRate: 2.0 / 3, BanRate: 1.0 / 3, ContestRate: 1}
+	if top != want {
+		t.Fatalf("top hero %+v, want %+v", top, want)
+	}
+	// Герой 3 тоже трижды в драфтах: при равенстве — по id
+	if second := stats.Heroes[1]; second.HeroID != 3 || second.Picks != 2 || second.Bans != 1 {
+		t.Fatalf("second hero %+v", second)
+	}
+
+	// С командой считаются только её пики и баны
+	stats, err = Aggregate(archive, Query{Team: "Liquid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byHero := make(map[int]HeroStats)
+	for _, h := range stats.Heroes {
+		byHero[h.HeroID] = h
+	}
+	if stats.Drafts != 2 || len(byHero) != 5 {
+		t.Fatalf("Liquid stats %+v", stats)
+	}
+	if h := byHero[1]; h.Picks != 2 || h.PickRate != 1 {
+		t.Fatalf("Liquid hero 1 %+v", h)
+	}
+	if h := byHero[3]; h.Picks != 0 || h.Bans != 1 || h.BanRate != 0.5 {
+		t.Fatalf("Liquid hero 3 %+v", h)
+	}
+}
+
+func TestSetWinner(t *testing.T) {
+	bolt, err := OpenBoltArchive(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bolt.Close()
+
+	for name, archive := range map[string]Archive{"memory": NewMemoryArchive(), "bolt": bolt} {
+		t.Run(name, func(t *testing.T) {
+			for _, s := range testSummaries() {
+				if err := archive.Add(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ResultRecorder(archive)(draft.GameResult{SessionID: "s3", Winner: draft.SideDire})
+			if err := archive.SetWinner("missing", draft.SideDire); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("unknown draft: %v", err)
+			}
+
+			page, err := Search(archive, Query{Team: "Liquid", Result: ResultWin})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(page.Drafts); !reflect.DeepEqual(got, []string{"s3"}) {
+				t.Fatalf("Liquid wins %v, want [s3]", got)
+			}
+			list, err := archive.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(list); !reflect.DeepEqual(got, []string{"s3", "s2", "s1"}) {
+				t.Fatalf("list %v, want newest first", got)
+			}
+		})
+	}
+}
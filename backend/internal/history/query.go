@@ -0,0 +1,190 @@
+package history
+
+import (
+	"strings"
+	"time"
+
+	"github.com/example/draftpractice/internal/draft"
+)
+
+// Исход игры с точки зрения выбранной стороны.
+const (
+	ResultWin  = "win"
+	ResultLoss = "loss"
+)
+
+// DefaultLimit и MaxLimit — размер страницы выдачи.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Query — фильтры поиска по истории. Пустые поля не фильтруют.
+type Query struct {
+	// Герой, который был пикнут или забанен (с Side — только этой стороной)
+	HeroID int
+	// Название команды, без учёта регистра (с Side — только на этой стороне)
+	Team string
+	Side draft.Side
+	// Формат драфта
+	Format string
+	// Границы времени завершения: From включительно, To — исключительно
+	From time.Time
+	To   time.Time
+	// win или loss для стороны команды Team (или Side) по записанному
+	// исходу игры; драфты без исхода не подходят
+	Result string
+	// Была ли сторона команды Team (или Side) фаворитом по оценке модели
+	// на момент завершения драфта; nil — не фильтровать
+	Favored *bool
+	// Размер страницы и id последней сводки предыдущей страницы
+	Limit  int
+	Cursor string
+}
+
+// Page — страница выдачи. NextCursor пуст на последней странице.
+type Page struct {
+	Drafts     []Summary `json:"drafts"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
+
+// Search возвращает страницу сводок из архива, подходящих под запрос.
+func Search(archive Archive, q Query) (Page, error) {
+	matched, err := filter(archive, q)
+	if err != nil {
+		return Page{}, err
+	}
+
+	start := 0
+	if q.Cursor != "" {
+		start = -1
+		for i, s := range matched {
+			if s.ID == q.Cursor {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return Page{}, ErrInvalidCursor
+		}
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	end := start + limit
+	page := Page{Drafts: []Summary{}}
+	if end < len(matched) {
+		page.NextCursor = matched[end-1].ID
+	} else {
+		end = len(matched)
+	}
+	page.Drafts = append(page.Drafts, matched[start:end]...)
+	return page, nil
+}
+
+// filter читает архив и оставляет сводки, подходящие под фильтры запроса.
+func filter(archive Archive, q Query) ([]Summary, error) {
+	all, err := archive.List()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]Summary, 0, len(all))
+	for _, s := range all {
+		if q.matches(s) {
+			matched = append(matched, s)
+		}
+	}
+	return matched, nil
+}
+
+func (q Query) matches(s Summary) bool {
+	if q.Format != "" && s.Format != q.Format {
+		return false
+	}
+	if !q.From.IsZero() && s.CompletedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !s.CompletedAt.Before(q.To) {
+		return false
+	}
+
+	sides := q.sides(s)
+	if len(sides) == 0 {
+		return false
+	}
+
+	if q.HeroID > 0 && !anySide(sides, func(side draft.Side) bool {
+		team := s.team(side)
+		return contains(team.Picks, q.HeroID) || contains(team.Bans, q.HeroID)
+	}) {
+		return false
+	}
+
+	if q.Result != "" {
+		if s.Winner == "" {
+			return false
+		}
+		won := q.Result == ResultWin
+		if !anySide(sides, func(side draft.Side) bool { return (side == s.Winner) == won }) {
+			return false
+		}
+	}
+
+	if q.Favored != nil {
+		favorite := s.Favorite()
+		if favorite == "" {
+			return false
+		}
+		if !anySide(sides, func(side draft.Side) bool { return (side == favorite) == *q.Favored }) {
+			return false
+		}
+	}
+	return true
+}
+
+// sides — стороны драфта, на которые распространяются фильтры команды и героя.
+func (q Query) sides(s Summary) []draft.Side {
+	var sides []draft.Side
+	for _, side := range []draft.Side{draft.SideRadiant, draft.SideDire} {
+		if q.Side != "" && side != q.Side {
+			continue
+		}
+		if q.Team != "" && !strings.EqualFold(s.team(side).Name, q.Team) {
+			continue
+		}
+		sides = append(sides, side)
+	}
+	return sides
+}
+
+func (s Summary) team(side draft.Side) draft.Team {
+	if side == draft.SideRadiant {
+		return s.Radiant
+	}
+	return s.Dire
+}
+
+func anySide(sides []draft.Side, fn func(draft.Side) bool) bool {
+	for _, side := range sides {
+		if fn(side) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
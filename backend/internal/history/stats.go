@@ -0,0 +1,65 @@
+package history
+
+import "sort"
+
+// HeroStats — частота пиков и банов героя в выборке драфтов.
+type HeroStats struct {
+	HeroID      int     `json:"heroId"`
+	Picks       int     `json:"picks"`
+	Bans        int     `json:"bans"`
+	PickRate    float64 `json:"pickRate"`
+	BanRate     float64 `json:"banRate"`
+	ContestRate float64 `json:"contestRate"`
+}
+
+// Stats — агрегаты по драфтам, подходящим под запрос.
+type Stats struct {
+	Drafts int         `json:"drafts"`
+	Heroes []HeroStats `json:"heroes"`
+}
+
+// Aggregate считает частоты пиков и банов. Если в запросе задана команда
+// или сторона, учитываются только её пики и баны. Limit и Cursor игнорируются.
+func Aggregate(archive Archive, q Query) (Stats, error) {
+	matched, err := filter(archive, q)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	byHero := make(map[int]*HeroStats)
+	get := func(id int) *HeroStats {
+		if byHero[id] == nil {
+			byHero[id] = &HeroStats{HeroID: id}
+		}
+		return byHero[id]
+	}
+
+	for _, s := range matched {
+		for _, side := range q.sides(s) {
+			team := s.team(side)
+			for _, id := range team.Picks {
+				get(id).Picks++
+			}
+			for _, id := range team.Bans {
+				get(id).Bans++
+			}
+		}
+	}
+
+	stats := Stats{Drafts: len(matched), Heroes: make([]HeroStats, 0, len(byHero))}
+	for _, h := range byHero {
+		n := float64(stats.Drafts)
+		h.PickRate = float64(h.Picks) / n
+		h.BanRate = float64(h.Bans) / n
+		h.ContestRate = float64(h.Picks+h.Bans) / n
+		stats.Heroes = append(stats.Heroes, *h)
+	}
+	sort.Slice(stats.Heroes, func(i, j int) bool {
+		a, b := stats.Heroes[i], stats.Heroes[j]
+		if a.Picks+a.Bans != b.Picks+b.Bans {
+			return a.Picks+a.Bans > b.Picks+b.Bans
+		}
+		return a.HeroID < b.HeroID
+	})
+	return stats, nil
+}
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/example/draftpractice/internal/draft"
+)
+
+// Допустимая скорость воспроизведения повтора.
+const (
+	minReplaySpeed = 0.5
+	maxReplaySpeed = 8
+)
+
+// replayControl — команда клиента плееру повтора вместе с каналом ответа.
+type replayControl struct {
+	msg   clientMessage
+	reply chan serverReply
+}
+
+// replayHandler воспроизводит журнал сессии через WebSocket
+// (/stream?mode=replay&speed=2&step=5): кадры приходят с исходными интервалами,
+// делёнными на скорость. Клиент управляет плеером сообщениями seek, speed,
+// pause, play и ping.
+func replayHandler(store *draft.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/sessions/")
+		id := strings.TrimSpace(strings.TrimSuffix(path, "/stream"))
+
+		session, err := store.GetSession(id)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		replay, err := draft.NewReplay(session)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		speed := 1.0
+		if v := r.URL.Query().Get("speed"); v != "" {
+			if speed, err = parseReplaySpeed(v); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		step := 0
+		if v := r.URL.Query().Get("step"); v != "" {
+			if step, err = strconv.Atoi(v); err != nil || step < 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid step"})
+				return
+			}
+		}
+
+		raw, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			http.Error(w, "failed to upgrade websocket", http.StatusInternalServerError)
+			return
+		}
+		defer raw.Close()
+		conn := &wsConn{conn: raw}
+
+		fmt.Printf("[WS] Replay of session %s at %.1fx from step %d\n", id, speed, step)
+
+		controls := make(chan replayControl)
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				_, data, err := raw.ReadMessage()
+				if err != nil {
+					return
+				}
+				var msg clientMessage
+				if err := json.Unmarshal(data, &msg); err != nil {
+					conn.send(serverReply{V: protocolVersion, Type: "error", Error: "invalid message"})
+					continue
+				}
+				ctl := replayControl{msg: msg, reply: make(chan serverReply, 1)}
+				select {
+				case controls <- ctl:
+				case <-closed:
+					return
+				}
+				conn.send(<-ctl.reply)
+			}
+		}()
+
+		conn.event("replay", map[string]any{
+			"sessionId":  replay.SessionID,
+			"format":     replay.Format,
+			"startedAt":  replay.StartedAt,
+			"durationMs": replay.DurationMs,
+			"completed":  replay.Completed,
+			"frames":     len(replay.Frames),
+			"speed":      speed,
+		})
+
+		p := &replayPlayer{replay: replay, speed: speed, playing: true}
+		p.seek(step)
+		for {
+			var due <-chan time.Time
+			if (p.playing || p.immediate) && p.next < len(replay.Frames) {
+				due = time.After(time.Until(p.deadline))
+			}
+
+			select {
+			case <-closed:
+				return
+			case ctl := <-controls:
+				ctl.reply <- p.control(ctl.msg)
+			case <-due:
+				frame := replay.Frames[p.next]
+				if err := conn.event(frame.Type, frame); err != nil {
+					return
+				}
+				p.advance()
+				if p.next == len(replay.Frames) {
+					conn.event("complete", "replay finished")
+				}
+			}
+		}
+	}
+}
+
+// replayPlayer — позиция и скорость воспроизведения одного соединения.
+type replayPlayer struct {
+	replay  draft.Replay
+	speed   float64
+	playing bool
+	// Следующий кадр и момент, когда его отправить
+	next     int
+	deadline time.Time
+	// Сколько оставалось до следующего кадра в момент паузы
+	remaining time.Duration
+	// Кадр после seek отправляется сразу, даже на паузе
+	immediate bool
+}
+
+// seek переходит к началу хода step; кадр отправляется сразу.
+func (p *replayPlayer) seek(step int) {
+	p.next = p.replay.FrameIndex(step)
+	p.deadline = time.Now()
+	p.immediate = true
+}
+
+// advance переводит плеер на следующий кадр и планирует его отправку
+// (на паузе — запоминает, сколько останется ждать после play).
+func (p *replayPlayer) advance() {
+	p.immediate = false
+	p.next++
+	if p.next >= len(p.replay.Frames) {
+		return
+	}
+	gap := p.replay.Frames[p.next].OffsetMs - p.replay.Frames[p.next-1].OffsetMs
+	wait := time.Duration(float64(gap)/p.speed) * time.Millisecond
+	if p.playing {
+		p.deadline = time.Now().Add(wait)
+	} else {
+		p.remaining = wait
+	}
+}
+
+// control выполняет команду клиента и готовит ответ.
+func (p *replayPlayer) control(msg clientMessage) serverReply {
+	reply := serverReply{V: protocolVersion, ID: msg.ID, Type: "ack"}
+	fail := func(format string, args ...any) serverReply {
+		reply.Type = "error"
+		reply.Error = fmt.Sprintf(format, args...)
+		return reply
+	}
+
+	if msg.V != protocolVersion {
+		return fail("unsupported protocol version %d", msg.V)
+	}
+
+	switch msg.Type {
+	case "ping":
+		reply.Data = map[string]any{"pong": true, "serverTime": time.Now().UTC()}
+
+	case "seek":
+		var data struct {
+			Step int `json:"step"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil || data.Step < 0 {
+			return fail("invalid seek payload")
+		}
+		p.seek(data.Step)
+
+	case "speed":
+		var data struct {
+			Speed float64 `json:"speed"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return fail("invalid speed payload")
+		}
+		if data.Speed < minReplaySpeed || data.Speed > maxReplaySpeed {
+			return fail("speed must be between %.1f and %.0f", minReplaySpeed, float64(maxReplaySpeed))
+		}
+		// Оставшееся ожидание масштабируется под новую скорость
+		scale := p.speed / data.Speed
+		if p.playing && !p.immediate {
+			p.deadline = time.Now().Add(time.Duration(float64(time.Until(p.deadline)) * scale))
+		}
+		p.remaining = time.Duration(float64(p.remaining) * scale)
+		p.speed = data.Speed
+
+	case "pause":
+		if p.playing {
+			p.playing = false
+			if !p.immediate {
+				p.remaining = max(time.Until(p.deadline), 0)
+			}
+		}
+
+	case "play":
+		if !p.playing {
+			p.playing = true
+			p.deadline = time.Now().Add(p.remaining)
+		}
+
+	default:
+		return fail("unknown message type %q", msg.Type)
+	}
+
+	reply.Data = map[string]any{"frame": p.next, "speed": p.speed, "playing": p.playing}
+	return reply
+}
+
+// parseReplaySpeed разбирает множитель скорости повтора.
+func parseReplaySpeed(v string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(v, "x"), 64)
+	if err != nil || speed < minReplaySpeed || speed > maxReplaySpeed {
+		return 0, fmt.Errorf("speed must be between %.1f and %.0f", minReplaySpeed, float64(maxReplaySpeed))
+	}
+	return speed, nil
+}
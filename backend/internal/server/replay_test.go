@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/example/draftpractice/internal/draft"
+)
+
+// playedSession — сессия с двумя банами, сделанными через 2 и 4 секунды.
+func playedSession(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	srv, _, clock := testServer(t)
+	id := createTestSession(t, srv)
+	for _, hero := range []int{1, 2} {
+		clock.Advance(2 * time.Second)
+		status := postJSON(t, srv.URL+"/api/sessions/"+id+"/action",
+			map[string]any{"type": "ban", "heroId": hero}, nil)
+		if status != http.StatusOK {
+			t.Fatalf("ban %d: status %d", hero, status)
+		}
+	}
+	return srv, id
+}
+
+func getReplay(t *testing.T, srv *httptest.Server, id string) draft.Replay {
+	t.Helper()
+	resp, err := http.Get(srv.URL + "/api/sessions/" + id + "/replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var replay draft.Replay
+	if err := json.NewDecoder(resp.Body).Decode(&replay); resp.StatusCode != http.StatusOK || err != nil {
+		t.Fatalf("replay: status %d, %v", resp.StatusCode, err)
+	}
+	return replay
+}
+
+func TestReplayEndpoint(t *testing.T) {
+	srv, id := playedSession(t)
+	replay := getReplay(t, srv, id)
+
+	if replay.SessionID != id || replay.Format != draft.DefaultFormat || replay.Completed {
+		t.Fatalf("replay header %+v", replay)
+	}
+	var actions []int
+	var last int64
+	for i, f := range replay.Frames {
+		if f.OffsetMs < last {
+			t.Fatalf("frame %d at %d ms goes before %d ms", i, f.OffsetMs, last)
+		}
+		last = f.OffsetMs
+		if f.Type == draft.EventAction {
+			actions = append(actions, f.Action.HeroID)
+			if want := int64(2000 * len(actions)); f.OffsetMs != want {
+				t.Errorf("ban %d at %d ms, want %d", f.Action.HeroID, f.OffsetMs, want)
+			}
+		}
+	}
+	if len(actions) != 2 || actions[0] != 1 || actions[1] != 2 {
+		t.Fatalf("actions %v, want [1 2]", actions)
+	}
+	if f := replay.Frames[0]; f.Type != draft.EventTimer || f.OffsetMs != 0 {
+		t.Fatalf("first frame %+v, want a timer frame at 0", f)
+	}
+
+	resp, err := http.Get(srv.URL + "/api/sessions/missing/replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("missing session: status %d", resp.StatusCode)
+	}
+}
+
+func TestReplayStreamPlaysFramesInOrder(t *testing.T) {
+	srv, id := playedSession(t)
+	replay := getReplay(t, srv, id)
+	conn := dial(t, srv, "/api/sessions/"+id+"/stream?mode=replay&speed=8&step=1")
+
+	frame := readFrame(t, conn)
+	var header struct {
+		Frames int     `json:"frames"`
+		Speed  float64 `json:"speed"`
+	}
+	if err := json.Unmarshal(frame.Data, &header); frame.Event != "replay" || err != nil ||
+		header.Frames != len(replay.Frames) || header.Speed != 8 {
+		t.Fatalf("header %+v", frame)
+	}
+
+	// С step=1 воспроизведение начинается с первого бана
+	var actions []int
+	for {
+		frame = readFrame(t, conn)
+		if frame.Event == "complete" {
+			break
+		}
+		var f draft.ReplayFrame
+		if err := json.Unmarshal(frame.Data, &f); err != nil {
+			t.Fatal(err)
+		}
+		if f.Action != nil {
+			actions = append(actions, f.Action.HeroID)
+		} else if len(actions) == 0 {
+			t.Fatalf("frame %+v before the seeked step", frame)
+		}
+	}
+	if len(actions) != 2 || actions[0] != 1 || actions[1] != 2 {
+		t.Fatalf("actions %v, want [1 2]", actions)
+	}
+}
+
+func TestReplayStreamControls(t *testing.T) {
+	srv, id := playedSession(t)
+	replay := getReplay(t, srv, id)
+	conn := dial(t, srv, "/api/sessions/"+id+"/stream?mode=replay&speed=0.5")
+	if frame := readFrame(t, conn); frame.Event != "replay" {
+		t.Fatalf("first frame %+v, want replay", frame)
+	}
+
+	reply, _ := sendMessage(t, conn, map[string]any{"v": 1, "id": "1", "type": "pause"})
+	if reply.Type != "ack" {
+		t.Fatalf("pause reply %+v", reply)
+	}
+
+	// seek на паузе сразу отдаёт кадр нужного хода
+	reply, events := sendMessage(t, conn, map[string]any{"v": 1, "id": "2", "type": "seek", "data": map[string]any{"step": 2}})
+	var position struct {
+		Frame   int  `json:"frame"`
+		Playing bool `json:"playing"`
+	}
+	if err := json.Unmarshal(reply.Data, &position); reply.Type != "ack" || err != nil ||
+		position.Frame != replay.FrameIndex(2) || position.Playing {
+		t.Fatalf("seek reply %+v", reply)
+	}
+	// Кадр может обогнать ответ на seek
+	if len(events) == 0 {
+		events = append(events, readFrame(t, conn))
+	}
+	frame := events[0]
+	var f draft.ReplayFrame
+	if err := json.Unmarshal(frame.Data, &f); err != nil || f.Action == nil || f.Action.HeroID != 2 {
+		t.Fatalf("frame after seek %+v", frame)
+	}
+
+	tests := []struct {
+		msg  map[string]any
+		want string
+	}{
+		{map[string]any{"v": 1, "id": "3", "type": "speed", "data": map[string]any{"speed": 16}}, "error"},
+		{map[string]any{"v": 1, "id": "4", "type": "seek", "data": map[string]any{"step": -1}}, "error"},
+		{map[string]any{"v": 1, "id": "5", "type": "speed", "data": map[string]any{"speed": 4}}, "ack"},
+		{map[string]any{"v": 1, "id": "6", "type": "rewind"}, "error"},
+	}
+	for _, tt := range tests {
+		if reply, _ := sendMessage(t, conn, tt.msg); reply.Type != tt.want {
+			t.Errorf("%v: reply %+v, want %s", tt.msg, reply, tt.want)
+		}
+	}
+}
+
+func TestReplayStreamRejectsBadParameters(t *testing.T) {
+	srv, id := playedSession(t)
+	for _, query := range []string{"speed=16", "speed=fast", "step=-1"} {
+		resp, err := http.Get(srv.URL + "/api/sessions/" + id + "/stream?mode=replay&" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
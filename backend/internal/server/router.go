@@ -2,27 +2,49 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/gorilla/websocket"
+	"github.com/example/draftpractice/internal/analytics"
 	"github.com/example/draftpractice/internal/draft"
 	"github.com/example/draftpractice/internal/heroes"
+	"github.com/example/draftpractice/internal/history"
 )
 
 type RouterConfig struct {
 	DraftStore *draft.Store
+	// Каталог героев; nil — встроенный в бинарник снимок
+	Heroes *heroes.Catalog
+	// Матрица контрпиков и синергий; nil — эндпоинт матчапов недоступен
+	Matchups *heroes.Matrix
+	// Модель вероятности победы; nil — эндпоинт оценки недоступен
+	WinModel *analytics.Model
+	// Архив завершённых драфтов; nil — эндпоинты истории недоступны
+	History history.Archive
 }
 
 func NewHandler(cfg RouterConfig) http.Handler {
+	if cfg.Heroes == nil {
+		cfg.Heroes = heroes.SnapshotCatalog()
+	}
 	mux := http.NewServeMux()
 
 	// ---- Healthcheck ----
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		writeJSON(w, http.StatusOK, map[string]any{
+			"status": "ok",
+			"heroes": map[string]any{
+				"source":    cfg.Heroes.Source(),
+				"fetchedAt": cfg.Heroes.FetchedAt(),
+				"count":     len(cfg.Heroes.All()),
+			},
+		})
 	})
 
 	// ---- Герои ----
@@ -31,7 +53,124 @@ func NewHandler(cfg RouterConfig) http.Handler {
 			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
 			return
 		}
-		writeJSON(w, http.StatusOK, heroes.All())
+
+		query := r.URL.Query()
+		list := heroes.Search(cfg.Heroes.All(), heroes.Filter{
+			Query:       query.Get("q"),
+			PrimaryAttr: query.Get("attr"),
+			AttackType:  query.Get("attackType"),
+			Role:        query.Get("role"),
+		})
+
+		// patch=7.32e — только пул Captains Mode этого патча
+		if version := query.Get("patch"); version != "" {
+			patch, err := cfg.Heroes.Patch(strings.ToLower(version))
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			legal := list[:0]
+			for _, h := range list {
+				if patch.InCM(h.ID) {
+					legal = append(legal, h)
+				}
+			}
+			list = legal
+		}
+
+		// available=sessionId — только герои, которыми можно сделать текущий ход
+		// сессии: в её пуле, ещё не взятые и не запрещённые стороне для пика
+		if sessionID := query.Get("available"); sessionID != "" {
+			session, err := cfg.DraftStore.GetSession(sessionID)
+			if err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			available := list[:0]
+			for _, h := range list {
+				if session.IsHeroAvailable(h.ID) {
+					available = append(available, h)
+				}
+			}
+			list = available
+		}
+
+		// Без bracket и sort — герои как есть, иначе с метриками брекета
+		bracketName, sortKey := query.Get("bracket"), strings.ToLower(query.Get("sort"))
+		if bracketName == "" && sortKey == "" {
+			writeJSON(w, http.StatusOK, list)
+			return
+		}
+
+		bracket := heroes.BracketRanked
+		if bracketName != "" {
+			parsed, err := heroes.ParseBracket(strings.ToLower(bracketName))
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			bracket = parsed
+		}
+
+		rated := heroes.Rate(list, cfg.Heroes.All(), bracket)
+		if sortKey != "" {
+			if err := heroes.SortRated(rated, sortKey); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, rated)
+	})
+
+	// ---- Патчи: состав героев и запреты Captains Mode ----
+	mux.HandleFunc("/api/patches", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, cfg.Heroes.Patches())
+	})
+
+	// ---- Матчапы героя: GET /api/heroes/{id}/matchups ----
+	mux.HandleFunc("/api/heroes/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/heroes/"), "/")
+		parts := strings.Split(path, "/")
+		if len(parts) != 2 || parts[1] != "matchups" {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown endpoint"})
+			return
+		}
+
+		heroID, err := strconv.Atoi(parts[0])
+		if err != nil || heroID <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid hero id"})
+			return
+		}
+
+		if cfg.Matchups == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "matchup data is not configured"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"heroId":    heroID,
+			"matchups":  matchupRows(cfg.Matchups.Matchups(heroID)),
+			"synergies": matchupRows(cfg.Matchups.Synergies(heroID)),
+			"fetchedAt": cfg.Matchups.FetchedAt(),
+		})
+	})
+
+	// ---- Форматы драфта ----
+	mux.HandleFunc("/api/formats", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, draft.Formats())
 	})
 
 	// ---- Создание новой сессии ----
@@ -47,6 +186,14 @@ func NewHandler(cfg RouterConfig) http.Handler {
 			FirstPick string `json:"firstPick"`
 			BotSide   string `json:"botSide"`
 			BotSpeed  string `json:"botSpeed"`
+			BotType   string `json:"botType"`
+			Format    string `json:"format"`
+			// Версия патча; пустая — последний
+			Patch string `json:"patch"`
+			// Тренировка с откатом ходов; по умолчанию выключена
+			Practice bool `json:"practice"`
+			// Бюджет пауз каждой команды в секундах; 0 — без ограничения
+			PauseBudget int `json:"pauseBudget"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -74,45 +221,199 @@ func NewHandler(cfg RouterConfig) http.Handler {
 		}
 
 		// скорость бота
-		botSpeed := strings.ToLower(req.BotSpeed)
-		switch botSpeed {
-		case "fast", "slow":
-		default:
-			botSpeed = "medium"
-		}
+		botSpeed := normalizeBotSpeed(req.BotSpeed)
 
-		// какая сторона бот
+		// какая сторона бот; none — две команды людей
 		botSide := draft.SideDire
 		switch strings.ToLower(req.BotSide) {
 		case "radiant":
 			botSide = draft.SideRadiant
 		case "dire":
 			botSide = draft.SideDire
+		case "none":
+			botSide = ""
 		}
 
 		// создаём сессию
-		session, err := cfg.DraftStore.CreateSession(
-			r.Context(),
-			req.Radiant,
-			req.Dire,
-			firstPick,
-			botSide,
-			botSpeed,
-		)
+		session, err := cfg.DraftStore.CreateSession(r.Context(), draft.SessionConfig{
+			RadiantName: req.Radiant,
+			DireName:    req.Dire,
+			FirstPick:   firstPick,
+			BotSide:     botSide,
+			BotSpeed:    botSpeed,
+			BotType:     strings.ToLower(req.BotType),
+			Format:      strings.ToLower(req.Format),
+			Patch:       strings.ToLower(req.Patch),
+			Practice:    req.Practice,
+			PauseBudget: req.PauseBudget,
+		})
+		if errors.Is(err, draft.ErrUnknownBot) || errors.Is(err, draft.ErrUnknownFormat) || errors.Is(err, heroes.ErrUnknownPatch) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
 
-		fmt.Printf("[DEBUG] Created session: %s (bot=%s, speed=%s, firstPick=%s)\n",
-			session.ID, botSide, botSpeed, firstPick)
+		fmt.Printf("[DEBUG] Created session: %s (bot=%s %s, speed=%s, firstPick=%s)\n",
+			session.ID, botSide, session.BotType, botSpeed, firstPick)
+
+		writeJSON(w, http.StatusCreated, createdSession{session, session.CoachKey})
+	})
+
+	// ---- Серии (Bo1/Bo3/Bo5) ----
+	mux.HandleFunc("/api/series", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		var req struct {
+			TeamA     string `json:"teamA"`
+			TeamB     string `json:"teamB"`
+			BestOf    int    `json:"bestOf"`
+			Fearless  string `json:"fearless"`
+			SideRule  string `json:"sideRule"`
+			Radiant   string `json:"radiant"`
+			FirstPick string `json:"firstPick"`
+			BotTeam   string `json:"botTeam"`
+			BotSpeed  string `json:"botSpeed"`
+			BotType   string `json:"botType"`
+			Format    string `json:"format"`
+			Patch     string `json:"patch"`
+
+			PauseBudget int `json:"pauseBudget"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+			return
+		}
+
+		series, err := cfg.DraftStore.CreateSeries(r.Context(), draft.SeriesConfig{
+			TeamA:     req.TeamA,
+			TeamB:     req.TeamB,
+			BestOf:    req.BestOf,
+			Fearless:  draft.FearlessMode(strings.ToLower(req.Fearless)),
+			SideRule:  draft.SideRule(strings.ToLower(req.SideRule)),
+			Radiant:   req.Radiant,
+			FirstPick: req.FirstPick,
+			BotTeam:   req.BotTeam,
+			BotSpeed:  normalizeBotSpeed(req.BotSpeed),
+			BotType:   strings.ToLower(req.BotType),
+			Format:    strings.ToLower(req.Format),
+			Patch:     strings.ToLower(req.Patch),
+
+			PauseBudget: req.PauseBudget,
+		})
+		if err != nil {
+			writeJSON(w, seriesErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, createdSeries{series, series.CoachKey})
+	})
+
+	mux.HandleFunc("/api/series/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/series/"), "/")
+		parts := strings.Split(path, "/")
+		id := parts[0]
 
-		writeJSON(w, http.StatusCreated, session)
+		// GET /api/series/{id}
+		if len(parts) == 1 && id != "" && r.Method == http.MethodGet {
+			series, err := cfg.DraftStore.GetSeries(id)
+			if err != nil {
+				writeJSON(w, seriesErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, series)
+			return
+		}
+
+		// POST /api/series/{id}/next
+		if len(parts) == 2 && parts[1] == "next" && r.Method == http.MethodPost {
+			var req struct {
+				Winner    string `json:"winner"`
+				Radiant   string `json:"radiant"`
+				FirstPick string `json:"firstPick"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+				return
+			}
+
+			series, err := cfg.DraftStore.NextGame(id, draft.NextGameRequest{
+				Winner:    req.Winner,
+				Radiant:   req.Radiant,
+				FirstPick: req.FirstPick,
+			})
+			if err != nil {
+				writeJSON(w, seriesErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, series)
+			return
+		}
+
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown endpoint"})
+	})
+
+	// ---- История завершённых драфтов ----
+	mux.HandleFunc("/api/drafts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		if cfg.History == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "draft history is not configured"})
+			return
+		}
+
+		query, err := parseHistoryQuery(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+
+		page, err := history.Search(cfg.History, query)
+		if err != nil {
+			writeJSON(w, historyErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, page)
+	})
+
+	// ---- Частоты пиков и банов по истории: GET /api/drafts/stats ----
+	mux.HandleFunc("/api/drafts/stats", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		if cfg.History == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "draft history is not configured"})
+			return
+		}
+
+		query, err := parseHistoryQuery(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+
+		stats, err := history.Aggregate(cfg.History, query)
+		if err != nil {
+			writeJSON(w, historyErrorStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, stats)
 	})
 
 	// ---- Получение сессии, экшены и WebSocket ----
 	mux.HandleFunc("/api/sessions/", func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasSuffix(r.URL.Path, "/stream") {
+			if r.URL.Query().Get("mode") == "replay" {
+				replayHandler(cfg.DraftStore).ServeHTTP(w, r)
+				return
+			}
 			streamHandler(cfg.DraftStore).ServeHTTP(w, r)
 			return
 		}
@@ -138,95 +439,364 @@ func NewHandler(cfg RouterConfig) http.Handler {
 			return
 		}
 
+		// GET /api/sessions/{id}/events
+		if len(parts) == 2 && parts[1] == "events" && r.Method == http.MethodGet {
+			session, err := cfg.DraftStore.GetSession(id)
+			if err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, session.Events)
+			return
+		}
+
+		// GET /api/sessions/{id}/replay
+		if len(parts) == 2 && parts[1] == "replay" && r.Method == http.MethodGet {
+			session, err := cfg.DraftStore.GetSession(id)
+			if err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			replay, err := draft.NewReplay(session)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, replay)
+			return
+		}
+
+		// GET /api/sessions/{id}/evaluation
+		if len(parts) == 2 && parts[1] == "evaluation" && r.Method == http.MethodGet {
+			if cfg.WinModel == nil {
+				writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "win probability model is not configured"})
+				return
+			}
+			session, err := cfg.DraftStore.GetSession(id)
+			if err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, analytics.Evaluate(cfg.WinModel, session))
+			return
+		}
+
 		// POST /api/sessions/{id}/action
 		if len(parts) == 2 && parts[1] == "action" && r.Method == http.MethodPost {
 			var req struct {
 				Type   string `json:"type"`
 				HeroID int    `json:"heroId"`
+				// Имя или прозвище героя ("ES", "wr") вместо heroId
+				Hero  string `json:"hero"`
+				Token string `json:"token"`
 			}
 			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
 				return
 			}
+			if req.HeroID == 0 && req.Hero != "" {
+				hero, err := cfg.Heroes.Resolve(req.Hero)
+				if err != nil {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+					return
+				}
+				req.HeroID = hero.ID
+			}
 
 			actionType := draft.Phase(req.Type)
-			session, err := cfg.DraftStore.ApplyAction(id, actionType, req.HeroID)
+			session, err := cfg.DraftStore.ApplyAction(id, lobbyToken(r, req.Token), actionType, req.HeroID)
 			if err != nil {
-				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				writeJSON(w, sessionErrorStatus(err), map[string]string{"error": err.Error()})
 				return
 			}
 			writeJSON(w, http.StatusOK, session)
 			return
 		}
 
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown endpoint"})
-	})
+		// POST /api/sessions/{id}/pause и /resume
+		if len(parts) == 2 && (parts[1] == "pause" || parts[1] == "resume") && r.Method == http.MethodPost {
+			var req struct {
+				Token string `json:"token"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+				return
+			}
 
-	return mux
-}
+			pauseOrResume := cfg.DraftStore.Pause
+			if parts[1] == "resume" {
+				pauseOrResume = cfg.DraftStore.Resume
+			}
+			session, err := pauseOrResume(id, lobbyToken(r, req.Token))
+			if err != nil {
+				writeJSON(w, sessionErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, session)
+			return
+		}
 
-// ---- JSON writer ----
-func writeJSON(w http.ResponseWriter, status int, payload any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(payload)
-}
+		// POST /api/sessions/{id}/undo
+		if len(parts) == 2 && parts[1] == "undo" && r.Method == http.MethodPost {
+			var req struct {
+				Count int    `json:"count"`
+				Token string `json:"token"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+				return
+			}
+			if req.Count == 0 {
+				req.Count = 1
+			}
 
-// ---- WebSocket ----
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool { return true },
-}
+			session, err := cfg.DraftStore.Undo(id, lobbyToken(r, req.Token), req.Count)
+			if err != nil {
+				writeJSON(w, sessionErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, session)
+			return
+		}
 
-func streamHandler(store *draft.Store) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		path := strings.TrimPrefix(r.URL.Path, "/api/sessions/")
-		path = strings.TrimSuffix(path, "/stream")
-		id := strings.TrimSpace(path)
+		// POST /api/sessions/{id}/fork?step=N (без step — от текущего состояния)
+		if len(parts) == 2 && parts[1] == "fork" && r.Method == http.MethodPost {
+			source, err := cfg.DraftStore.GetSession(id)
+			if err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			step := len(source.Actions())
+			if v := r.URL.Query().Get("step"); v != "" {
+				if step, err = strconv.Atoi(v); err != nil {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid step"})
+					return
+				}
+			}
 
-		conn, err := upgrader.Upgrade(w, r, nil)
-		if err != nil {
-			http.Error(w, "failed to upgrade websocket", http.StatusInternalServerError)
+			session, err := cfg.DraftStore.Fork(id, step)
+			if err != nil {
+				writeJSON(w, sessionErrorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusCreated, createdSession{session, session.CoachKey})
 			return
 		}
-		defer conn.Close()
 
-		fmt.Printf("[WS] New connection for session %s\n", id)
+		// POST /api/sessions/{id}/join
+		if len(parts) == 2 && parts[1] == "join" && r.Method == http.MethodPost {
+			var req struct {
+				Name string `json:"name"`
+				Role string `json:"role"`
+				Side string `json:"side"`
+				// Ключ тренера из ответа на создание сессии (для role coach)
+				Key string `json:"key"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+				return
+			}
 
-		for {
-			session, err := store.GetSession(id)
+			member, err := cfg.DraftStore.Join(id, req.Name,
+				draft.LobbyRole(strings.ToLower(req.Role)), draft.Side(strings.ToLower(req.Side)), req.Key)
 			if err != nil {
-				conn.WriteJSON(map[string]any{
-					"event": "error",
-					"data":  "session not found",
-				})
+				writeJSON(w, sessionErrorStatus(err), map[string]string{"error": err.Error()})
 				return
 			}
+			writeJSON(w, http.StatusCreated, map[string]any{
+				"token":  member.Token,
+				"member": member,
+			})
+			return
+		}
 
-			payload := map[string]any{
-				"event": "tick",
-				"data": map[string]any{
-					"stage":          session.Stage,
-					"side":           session.Side,
-					"currentTimer":   session.CurrentTimer,
-					"reserveRadiant": session.ReserveRadiant,
-					"reserveDire":    session.ReserveDire,
-					"completed":      session.Completed,
-					"radiant":        session.Radiant,
-					"dire":           session.Dire,
-				},
+		// POST /api/sessions/{id}/suggest
+		if len(parts) == 2 && parts[1] == "suggest" && r.Method == http.MethodPost {
+			var req struct {
+				HeroID int    `json:"heroId"`
+				Token  string `json:"token"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+				return
 			}
 
-			conn.WriteJSON(payload)
-
-			if session.Completed {
-				conn.WriteJSON(map[string]any{
-					"event": "complete",
-					"data":  "draft finished",
-				})
+			if err := cfg.DraftStore.Suggest(id, lobbyToken(r, req.Token), req.HeroID); err != nil {
+				writeJSON(w, sessionErrorStatus(err), map[string]string{"error": err.Error()})
 				return
 			}
+			writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+			return
+		}
+
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown endpoint"})
+	})
 
-			time.Sleep(time.Second)
+	return mux
+}
+
+// createdSession — ответ на создание сессии: сама сессия и ключ тренера,
+// который выдаётся только её создателю.
+type createdSession struct {
+	*draft.DraftSession
+	CoachKey string `json:"coachKey"`
+}
+
+// createdSeries — ответ на создание серии с ключом тренера всех её игр.
+type createdSeries struct {
+	*draft.Series
+	CoachKey string `json:"coachKey"`
+}
+
+// matchupRows добавляет к строкам OpenDota посчитанное преимущество.
+func matchupRows(rows []heroes.Matchup) []map[string]any {
+	result := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, map[string]any{
+			"heroId":      row.HeroID,
+			"gamesPlayed": row.GamesPlayed,
+			"wins":        row.Wins,
+			"advantage":   row.Advantage(),
+		})
+	}
+	return result
+}
+
+// normalizeBotSpeed приводит скорость бота к fast, medium или slow.
+func normalizeBotSpeed(speed string) string {
+	speed = strings.ToLower(speed)
+	switch speed {
+	case "fast", "slow":
+		return speed
+	default:
+		return "medium"
+	}
+}
+
+// lobbyToken берёт токен участника лобби из тела запроса или заголовка X-Lobby-Token.
+func lobbyToken(r *http.Request, fromBody string) string {
+	if fromBody != "" {
+		return fromBody
+	}
+	return r.Header.Get("X-Lobby-Token")
+}
+
+// sessionErrorStatus подбирает HTTP-статус для ошибок действий в сессии.
+func sessionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, draft.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, draft.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// seriesErrorStatus подбирает HTTP-статус для ошибок серий.
+func seriesErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, draft.ErrSeriesNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, draft.ErrInvalidSeries),
+		errors.Is(err, draft.ErrUnknownBot),
+		errors.Is(err, draft.ErrUnknownFormat),
+		errors.Is(err, heroes.ErrUnknownPatch):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseHistoryQuery разбирает фильтры поиска по истории из query-параметров.
+// Даты from/to принимаются в RFC 3339 или как YYYY-MM-DD.
+func parseHistoryQuery(r *http.Request) (history.Query, error) {
+	values := r.URL.Query()
+	q := history.Query{
+		Team:   values.Get("team"),
+		Format: strings.ToLower(values.Get("format")),
+		Cursor: values.Get("cursor"),
+	}
+
+	if v := values.Get("hero"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			return q, fmt.Errorf("invalid hero %q", v)
+		}
+		q.HeroID = id
+	}
+
+	switch side := draft.Side(strings.ToLower(values.Get("side"))); side {
+	case "", draft.SideRadiant, draft.SideDire:
+		q.Side = side
+	default:
+		return q, fmt.Errorf("invalid side %q", side)
+	}
+
+	switch result := strings.ToLower(values.Get("result")); result {
+	case "":
+	case history.ResultWin, history.ResultLoss:
+		if q.Team == "" && q.Side == "" {
+			return q, fmt.Errorf("result filter requires team or side")
+		}
+		q.Result = result
+	default:
+		return q, fmt.Errorf("invalid result %q", result)
+	}
+
+	if raw := values.Get("favored"); raw != "" {
+		favored, err := strconv.ParseBool(raw)
+		if err != nil {
+			return q, fmt.Errorf("invalid favored %q", raw)
+		}
+		if q.Team == "" && q.Side == "" {
+			return q, fmt.Errorf("favored filter requires team or side")
+		}
+		q.Favored = &favored
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		v := values.Get(bound.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, v); err != nil {
+				return q, fmt.Errorf("invalid %s %q", bound.name, v)
+			}
+			// to=YYYY-MM-DD включает весь указанный день
+			if bound.name == "to" {
+				t = t.AddDate(0, 0, 1)
+			}
 		}
+		*bound.dst = t
+	}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return q, fmt.Errorf("invalid limit %q", v)
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
+
+// historyErrorStatus подбирает HTTP-статус для ошибок истории драфтов.
+func historyErrorStatus(err error) int {
+	if errors.Is(err, history.ErrInvalidCursor) {
+		return http.StatusBadRequest
 	}
+	return http.StatusInternalServerError
+}
+
+// ---- JSON writer ----
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
 }
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/example/draftpractice/internal/draft"
+	"github.com/example/draftpractice/internal/heroes"
+)
+
+func TestJoinAsCoachRequiresCreatorKey(t *testing.T) {
+	srv, _, _ := testServer(t)
+	var created struct {
+		ID       string `json:"ID"`
+		CoachKey string `json:"coachKey"`
+	}
+	status := postJSON(t, srv.URL+"/api/sessions", map[string]any{
+		"radiant": "Liquid", "dire": "Spirit", "firstPick": "radiant", "botSide": "none", "practice": true,
+	}, &created)
+	if status != http.StatusCreated || created.ID == "" || created.CoachKey == "" {
+		t.Fatalf("create: status %d, %+v", status, created)
+	}
+	joinURL := srv.URL + "/api/sessions/" + created.ID + "/join"
+
+	for _, key := range []string{"", "guess"} {
+		var reply map[string]string
+		status := postJSON(t, joinURL, map[string]any{"name": "Puppey", "role": "coach", "key": key}, &reply)
+		if status != http.StatusForbidden || !strings.Contains(reply["error"], "coach key") {
+			t.Fatalf("self-declared coach with key %q: status %d, %v", key, status, reply)
+		}
+	}
+
+	var joined struct {
+		Token string `json:"token"`
+	}
+	status = postJSON(t, joinURL, map[string]any{"name": "Puppey", "role": "coach", "key": created.CoachKey}, &joined)
+	if status != http.StatusCreated || joined.Token == "" {
+		t.Fatalf("coach with the creator key: status %d", status)
+	}
+
+	// Ключ не отдаётся тем, кто просто читает сессию
+	resp, err := http.Get(srv.URL + "/api/sessions/" + created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if strings.Contains(string(body), created.CoachKey) {
+		t.Fatalf("GET session leaks the coach key: %s", body)
+	}
+	var session map[string]any
+	if err := json.Unmarshal(body, &session); err != nil || session["ID"] != created.ID {
+		t.Fatalf("GET session: %v, %s", err, body)
+	}
+}
+
+// heroIDs запрашивает /api/heroes с параметрами query и возвращает id героев.
+func heroIDs(t *testing.T, srv *httptest.Server, query string) map[int]bool {
+	t.Helper()
+	resp, err := http.Get(srv.URL + "/api/heroes?" + query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/heroes?%s: status %d", query, resp.StatusCode)
+	}
+	var list []heroes.Hero
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[int]bool, len(list))
+	for _, h := range list {
+		ids[h.ID] = true
+	}
+	return ids
+}
+
+func TestHeroesAvailableFollowsCurrentTurn(t *testing.T) {
+	srv, store, _ := testServer(t)
+	const locked = 1
+	session, err := store.CreateSession(context.Background(), draft.SessionConfig{
+		RadiantName: "Liquid",
+		DireName:    "Spirit",
+		FirstPick:   draft.SideRadiant,
+		Locked:      map[draft.Side][]int{draft.SideRadiant: {locked}, draft.SideDire: {locked}},
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.Stage == draft.PhasePick {
+		t.Fatal("expected the draft to open with bans")
+	}
+	// Запрет на пик не мешает банить героя
+	if !heroIDs(t, srv, "available="+session.ID)[locked] {
+		t.Fatalf("hero %d locked for picks is not available for a ban", locked)
+	}
+
+	var banned []int
+	for session.Stage != draft.PhasePick {
+		heroID := 0
+		for _, h := range session.AvailableHeroes() {
+			if h.ID != locked {
+				heroID = h.ID
+				break
+			}
+		}
+		if session, err = store.ApplyAction(session.ID, "", session.Stage, heroID); err != nil {
+			t.Fatalf("ban %d: %v", heroID, err)
+		}
+		banned = append(banned, heroID)
+	}
+
+	available := heroIDs(t, srv, "available="+session.ID)
+	if available[locked] {
+		t.Errorf("hero %d is offered to %s although it is locked for their picks", locked, session.Side)
+	}
+	for _, id := range banned {
+		if available[id] {
+			t.Errorf("banned hero %d is still available", id)
+		}
+	}
+	if len(available) != len(session.AvailableHeroes()) {
+		t.Errorf("endpoint offers %d heroes, session allows %d", len(available), len(session.AvailableHeroes()))
+	}
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/draftpractice/internal/draft"
+	"github.com/gorilla/websocket"
+)
+
+// protocolVersion — версия JSON-протокола /stream.
+const protocolVersion = 1
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// clientMessage — сообщение клиента: action, hover, suggest, pause, resume или ping.
+// ID возвращается в ответе ack/error для сопоставления запроса и ответа.
+type clientMessage struct {
+	V    int             `json:"v"`
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// serverReply — ответ на сообщение клиента.
+type serverReply struct {
+	V     int    `json:"v"`
+	ID    string `json:"id"`
+	Type  string `json:"type"` // ack или error
+	Data  any    `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// wsConn сериализует запись: gorilla/websocket допускает одного писателя.
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *wsConn) send(payload any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(payload)
+}
+
+func (c *wsConn) event(name any, data any) error {
+	return c.send(map[string]any{"v": protocolVersion, "event": name, "data": data})
+}
+
+// streamHandler подписывает соединение на события сессии: сначала отправляет
+// текущее состояние, затем события по мере их появления в Store. Через то же
+// соединение клиент может делать ходы, отправлять hover и ping.
+func streamHandler(store *draft.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/sessions/")
+		path = strings.TrimSuffix(path, "/stream")
+		id := strings.TrimSpace(path)
+		// Токен участника лобби: для ходов и hover через это соединение
+		// и для получения подсказок своей стороны
+		token := r.URL.Query().Get("token")
+
+		raw, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			http.Error(w, "failed to upgrade websocket", http.StatusInternalServerError)
+			return
+		}
+		defer raw.Close()
+		conn := &wsConn{conn: raw}
+
+		state, events, unsubscribe, err := store.Subscribe(id, token)
+		if err != nil {
+			conn.event("error", "session not found")
+			return
+		}
+		defer unsubscribe()
+
+		fmt.Printf("[WS] New connection for session %s\n", id)
+
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				_, data, err := raw.ReadMessage()
+				if err != nil {
+					return
+				}
+				var msg clientMessage
+				if err := json.Unmarshal(data, &msg); err != nil {
+					conn.send(serverReply{V: protocolVersion, Type: "error", Error: "invalid message"})
+					continue
+				}
+				conn.send(handleClientMessage(store, id, token, msg))
+			}
+		}()
+
+		conn.event("state", state)
+		if state.Completed {
+			conn.event("complete", "draft finished")
+			return
+		}
+
+		for {
+			select {
+			case <-closed:
+				return
+			case event, ok := <-events:
+				if !ok {
+					// Hub отключил отстающего подписчика: клиент должен
+					// переподключиться и получить свежее состояние
+					conn.event("error", "too many pending events, reconnect")
+					return
+				}
+				if err := conn.event(event.Type, event); err != nil {
+					return
+				}
+				if event.Type == draft.EventCompleted {
+					conn.event("complete", "draft finished")
+					return
+				}
+			}
+		}
+	}
+}
+
+// handleClientMessage выполняет сообщение клиента и готовит ответ.
+func handleClientMessage(store *draft.Store, sessionID, token string, msg clientMessage) serverReply {
+	reply := serverReply{V: protocolVersion, ID: msg.ID, Type: "ack"}
+	fail := func(format string, args ...any) serverReply {
+		reply.Type = "error"
+		reply.Error = fmt.Sprintf(format, args...)
+		return reply
+	}
+
+	if msg.V != protocolVersion {
+		return fail("unsupported protocol version %d", msg.V)
+	}
+
+	switch msg.Type {
+	case "ping":
+		reply.Data = map[string]any{"pong": true, "serverTime": time.Now().UTC()}
+		return reply
+
+	case "action":
+		var data struct {
+			Type   string `json:"type"`
+			HeroID int    `json:"heroId"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return fail("invalid action payload")
+		}
+		session, err := store.ApplyAction(sessionID, token, draft.Phase(data.Type), data.HeroID)
+		if err != nil {
+			return fail("%s", err.Error())
+		}
+		reply.Data = session.State()
+		return reply
+
+	case "hover":
+		var data struct {
+			HeroID int `json:"heroId"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return fail("invalid hover payload")
+		}
+		if err := store.Hover(sessionID, token, data.HeroID); err != nil {
+			return fail("%s", err.Error())
+		}
+		return reply
+
+	case "suggest":
+		var data struct {
+			HeroID int `json:"heroId"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return fail("invalid suggest payload")
+		}
+		if err := store.Suggest(sessionID, token, data.HeroID); err != nil {
+			return fail("%s", err.Error())
+		}
+		return reply
+
+	case "pause", "resume":
+		pauseOrResume := store.Pause
+		if msg.Type == "resume" {
+			pauseOrResume = store.Resume
+		}
+		session, err := pauseOrResume(sessionID, token)
+		if err != nil {
+			return fail("%s", err.Error())
+		}
+		reply.Data = session.State()
+		return reply
+
+	default:
+		return fail("unknown message type %q", msg.Type)
+	}
+}
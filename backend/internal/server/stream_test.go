@@ -0,0 +1,284 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/example/draftpractice/internal/draft"
+	"github.com/gorilla/websocket"
+)
+
+// testServer — API поверх Store на FakeClock: таймеры сами не идут, бота нет.
+func testServer(t *testing.T) (*httptest.Server, *draft.Store, *draft.FakeClock) {
+	t.Helper()
+	clock := draft.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	store := draft.NewStoreWithConfig(draft.StoreConfig{Clock: clock})
+	srv := httptest.NewServer(NewHandler(RouterConfig{DraftStore: store}))
+	t.Cleanup(srv.Close)
+	return srv, store, clock
+}
+
+// postJSON отправляет body и декодирует ответ в out (если out не nil).
+func postJSON(t *testing.T, url string, body, out any) int {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s: %v", url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// createTestSession создаёт сессию двух команд людей, Radiant с первым пиком.
+func createTestSession(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	var session draft.DraftSession
+	status := postJSON(t, srv.URL+"/api/sessions", map[string]any{
+		"radiant":   "Liquid",
+		"dire":      "Spirit",
+		"firstPick": "radiant",
+		"botSide":   "none",
+	}, &session)
+	if status != http.StatusCreated {
+		t.Fatalf("create session: status %d", status)
+	}
+	return session.ID
+}
+
+// wsFrame — сообщение сервера: событие (event) или ответ на сообщение (type).
+type wsFrame struct {
+	V     int             `json:"v"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+	ID    string          `json:"id"`
+	Type  string          `json:"type"`
+	Error string          `json:"error"`
+}
+
+func dial(t *testing.T, srv *httptest.Server, path string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + path
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial %s: %v", path, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readFrame читает следующее сообщение, пропуская тики таймера.
+func readFrame(t *testing.T, conn *websocket.Conn) wsFrame {
+	t.Helper()
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var frame wsFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+		if frame.Event != string(draft.EventTimer) {
+			return frame
+		}
+	}
+}
+
+func TestStreamSendsStateThenEvents(t *testing.T) {
+	srv, _, _ := testServer(t)
+	id := createTestSession(t, srv)
+	conn := dial(t, srv, "/api/sessions/"+id+"/stream")
+
+	frame := readFrame(t, conn)
+	var state draft.SessionState
+	if err := json.Unmarshal(frame.Data, &state); frame.Event != "state" || err != nil {
+		t.Fatalf("first frame %+v (%v), want state", frame, err)
+	}
+	if state.Step != 0 || state.Side != draft.SideRadiant || state.Stage != draft.PhaseBan {
+		t.Fatalf("initial state %+v", state)
+	}
+
+	status := postJSON(t, srv.URL+"/api/sessions/"+id+"/action",
+		map[string]any{"type": "ban", "heroId": 1}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("action: status %d", status)
+	}
+
+	frame = readFrame(t, conn)
+	var event draft.Event
+	if err := json.Unmarshal(frame.Data, &event); frame.Event != "action" || err != nil {
+		t.Fatalf("frame %+v (%v), want action", frame, err)
+	}
+	if event.Action == nil || event.Action.HeroID != 1 || event.State.Step != 1 {
+		t.Fatalf("action event %+v", event)
+	}
+}
+
+func TestStreamUnknownSession(t *testing.T) {
+	srv, _, _ := testServer(t)
+	conn := dial(t, srv, "/api/sessions/missing/stream")
+
+	if frame := readFrame(t, conn); frame.Event != "error" {
+		t.Fatalf("frame %+v, want error", frame)
+	}
+}
+
+// sendMessage отправляет сообщение протокола и ждёт ответа с тем же id;
+// события, пришедшие раньше ответа, возвращаются вторым значением.
+func sendMessage(t *testing.T, conn *websocket.Conn, msg map[string]any) (wsFrame, []wsFrame) {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var events []wsFrame
+	for {
+		frame := readFrame(t, conn)
+		if frame.Event != "" {
+			events = append(events, frame)
+			continue
+		}
+		if frame.ID != msg["id"] {
+			t.Fatalf("reply %+v, want id %v", frame, msg["id"])
+		}
+		return frame, events
+	}
+}
+
+// openStream подключается к сессии и дочитывает начальное состояние.
+func openStream(t *testing.T, srv *httptest.Server, id, token string) *websocket.Conn {
+	t.Helper()
+	path := "/api/sessions/" + id + "/stream"
+	if token != "" {
+		path += "?token=" + token
+	}
+	conn := dial(t, srv, path)
+	if frame := readFrame(t, conn); frame.Event != "state" {
+		t.Fatalf("first frame %+v, want state", frame)
+	}
+	return conn
+}
+
+func TestStreamPing(t *testing.T) {
+	srv, _, _ := testServer(t)
+	conn := openStream(t, srv, createTestSession(t, srv), "")
+
+	reply, _ := sendMessage(t, conn, map[string]any{"v": 1, "id": "p1", "type": "ping"})
+	var data struct {
+		Pong bool `json:"pong"`
+	}
+	if err := json.Unmarshal(reply.Data, &data); reply.Type != "ack" || err != nil || !data.Pong {
+		t.Fatalf("ping reply %+v", reply)
+	}
+}
+
+func TestStreamActionAndHover(t *testing.T) {
+	srv, _, _ := testServer(t)
+	id := createTestSession(t, srv)
+	conn := openStream(t, srv, id, "")
+	watcher := openStream(t, srv, id, "")
+
+	reply, _ := sendMessage(t, conn, map[string]any{
+		"v": 1, "id": "h1", "type": "hover", "data": map[string]any{"heroId": 1},
+	})
+	if reply.Type != "ack" {
+		t.Fatalf("hover reply %+v", reply)
+	}
+	frame := readFrame(t, watcher)
+	var event draft.Event
+	if err := json.Unmarshal(frame.Data, &event); frame.Event != "hover" || err != nil ||
+		event.Hover == nil || event.Hover.HeroID != 1 || event.Hover.Side != draft.SideRadiant {
+		t.Fatalf("hover event %+v", frame)
+	}
+
+	reply, _ = sendMessage(t, conn, map[string]any{
+		"v": 1, "id": "a1", "type": "action", "data": map[string]any{"type": "ban", "heroId": 1},
+	})
+	var state draft.SessionState
+	if err := json.Unmarshal(reply.Data, &state); reply.Type != "ack" || err != nil || state.Step != 1 {
+		t.Fatalf("action reply %+v", reply)
+	}
+	frame = readFrame(t, watcher)
+	if err := json.Unmarshal(frame.Data, &event); frame.Event != "action" || err != nil ||
+		event.Action == nil || event.Action.HeroID != 1 {
+		t.Fatalf("action event %+v", frame)
+	}
+}
+
+func TestStreamRejectsInvalidMessages(t *testing.T) {
+	srv, _, _ := testServer(t)
+	conn := openStream(t, srv, createTestSession(t, srv), "")
+
+	tests := []struct {
+		name string
+		msg  map[string]any
+		want string
+	}{
+		{"old protocol", map[string]any{"v": 0, "id": "1", "type": "ping"}, "unsupported protocol version"},
+		{"unknown type", map[string]any{"v": 1, "id": "2", "type": "dance"}, "unknown message type"},
+		{"bad action payload", map[string]any{"v": 1, "id": "3", "type": "action", "data": "ban"}, "invalid action payload"},
+		{"bad hover payload", map[string]any{"v": 1, "id": "4", "type": "hover", "data": []int{1}}, "invalid hover payload"},
+		{"wrong phase", map[string]any{"v": 1, "id": "5", "type": "action",
+			"data": map[string]any{"type": "pick", "heroId": 1}}, "expected ban action"},
+		{"unsupported action", map[string]any{"v": 1, "id": "6", "type": "action",
+			"data": map[string]any{"type": "swap", "heroId": 1}}, "unsupported action type"},
+		{"unknown hero", map[string]any{"v": 1, "id": "7", "type": "hover",
+			"data": map[string]any{"heroId": 100000}}, "is not available"},
+	}
+	for _, tt := range tests {
+		reply, _ := sendMessage(t, conn, tt.msg)
+		if reply.Type != "error" || !strings.Contains(reply.Error, tt.want) {
+			t.Errorf("%s: reply %+v, want error %q", tt.name, reply, tt.want)
+		}
+	}
+
+	// Не-JSON не рвёт соединение
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{")); err != nil {
+		t.Fatal(err)
+	}
+	if reply := readFrame(t, conn); reply.Type != "error" || reply.Error != "invalid message" {
+		t.Fatalf("reply to garbage %+v", reply)
+	}
+	if reply, _ := sendMessage(t, conn, map[string]any{"v": 1, "id": "8", "type": "ping"}); reply.Type != "ack" {
+		t.Fatalf("ping after garbage %+v", reply)
+	}
+}
+
+func TestStreamRequiresCaptainToken(t *testing.T) {
+	srv, _, _ := testServer(t)
+	id := createTestSession(t, srv)
+	var joined struct {
+		Token string `json:"token"`
+	}
+	status := postJSON(t, srv.URL+"/api/sessions/"+id+"/join",
+		map[string]any{"name": "Miracle", "role": "captain", "side": "radiant"}, &joined)
+	if status != http.StatusCreated {
+		t.Fatalf("join: status %d", status)
+	}
+
+	anonymous := openStream(t, srv, id, "")
+	reply, _ := sendMessage(t, anonymous, map[string]any{
+		"v": 1, "id": "1", "type": "action", "data": map[string]any{"type": "ban", "heroId": 1},
+	})
+	if reply.Type != "error" || !strings.Contains(reply.Error, "forbidden") {
+		t.Fatalf("anonymous action reply %+v", reply)
+	}
+
+	captain := openStream(t, srv, id, joined.Token)
+	reply, _ = sendMessage(t, captain, map[string]any{
+		"v": 1, "id": "2", "type": "action", "data": map[string]any{"type": "ban", "heroId": 1},
+	})
+	if reply.Type != "ack" {
+		t.Fatalf("captain action reply %+v", reply)
+	}
+}